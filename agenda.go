@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// agendaGroups lists the agenda headings in display order
+var agendaGroups = []DueStatus{DueOverdue, DueToday, DueThisWeek, DueLater}
+
+// buildAgenda groups the indices of open tasks with a due date by due status,
+// ordering each group by due date
+func buildAgenda(items []Item, dates DateContext) map[DueStatus][]int {
+	agenda := make(map[DueStatus][]int)
+	hidden := hiddenItems(items, dates)
+
+	for i, item := range items {
+		if hidden[i] {
+			continue
+		}
+
+		status := dates.DueStatus(item)
+		if status == DueNone {
+			continue
+		}
+		agenda[status] = append(agenda[status], i)
+	}
+
+	for _, indices := range agenda {
+		slices.SortStableFunc(indices, func(a, b int) int {
+			dueA, _ := dates.Parse(items[a].Metadata["due"])
+			dueB, _ := dates.Parse(items[b].Metadata["due"])
+			return dueA.Compare(dueB)
+		})
+	}
+
+	return agenda
+}
+
+func newAgendaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "agenda",
+		Short: "Show open tasks grouped by due date",
+		Long: `Show open tasks with a due date grouped into overdue, today, this week and later.
+Due dates are read from the "due" metadata in ISO (2025-08-10) or relative (today, tomorrow, +3d, +2w) format.
+The week starts on the day set with 'tasks config week-start' (Monday by default).
+Snoozed tasks are hidden unless --all is passed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadFileConfig(filePath)
+			if err != nil {
+				return err
+			}
+			items, err := parseMarkdownFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			agenda := buildAgenda(items, newDateContext(cfg))
+
+			if porcelain != "" {
+				paths := sectionPaths(items)
+				for _, status := range agendaGroups {
+					for _, index := range agenda[status] {
+						printPorcelain("agenda", append([]any{strings.ToLower(status.String())}, itemFields(items[index], index, paths[index])...)...)
+					}
+				}
+				return nil
+			}
+
+			printed := 0
+			for _, status := range agendaGroups {
+				indices := agenda[status]
+				if len(indices) == 0 {
+					continue
+				}
+
+				if printed > 0 {
+					fmt.Println()
+				}
+				if shouldUseColor() {
+					fmt.Printf("\033[1m%s\033[0m\n", status)
+				} else {
+					fmt.Println(status)
+				}
+				for _, index := range indices {
+					fmt.Println(formatItem(items[index], index))
+				}
+				printed++
+			}
+
+			if printed == 0 {
+				fmt.Println("No open tasks with a due date")
+			}
+			return nil
+		},
+	}
+}
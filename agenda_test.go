@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAgenda(t *testing.T) {
+	content := `# Work
+- [ ] No date
+- [ ] Later due:2025-09-01
+- [ ] Overdue due:2025-08-01
+- [ ] Friday due:2025-08-15
+- [ ] Today due:today
+- [x] Done overdue due:2025-08-01
+- [ ] Thursday due:+1d
+- [ ] Older due:2025-07-01
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	agenda := buildAgenda(items, DateContext{Today: wednesday, WeekStart: time.Monday})
+
+	require.Equal(t, []int{8, 3}, agenda[DueOverdue], "Overdue tasks ordered by due date")
+	require.Equal(t, []int{5}, agenda[DueToday])
+	require.Equal(t, []int{7, 4}, agenda[DueThisWeek])
+	require.Equal(t, []int{2}, agenda[DueLater])
+	require.NotContains(t, agenda, DueNone)
+}
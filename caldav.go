@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// calDAVClient reads and writes the VTODOs of a CalDAV collection
+type calDAVClient struct {
+	URL      string // URL of the collection
+	User     string // Basic authentication, skipped when empty
+	Password string
+	HTTP     *http.Client
+}
+
+// calDAVObject is a VTODO stored on the server
+type calDAVObject struct {
+	Href string // Absolute URL of the calendar object
+	ETag string
+	Todo icsTodo
+}
+
+// calDAVQuery asks for the etag and content of every VTODO of the collection
+const calDAVQuery = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag/>
+    <c:calendar-data/>
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VTODO"/>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>
+`
+
+// calDAVMultistatus is the response to a calendar query
+type calDAVMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Prop struct {
+				ETag         string `xml:"DAV: getetag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// do sends a request to the server and checks the response status
+func (c *calDAVClient) do(method, target string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(req.Header, header)
+	if c.User != "" {
+		req.SetBasicAuth(c.User, c.Password)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("caldav: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		resp.Body.Close()
+		return nil, errorf(ExitConflict, "caldav: %s changed on the server during the sync, run it again", target)
+	case resp.StatusCode >= 300:
+		resp.Body.Close()
+		return nil, fmt.Errorf("caldav: %s %s: %s", method, target, resp.Status)
+	}
+	return resp, nil
+}
+
+// resolve returns the absolute URL of an href given by the server
+func (c *calDAVClient) resolve(href string) (string, error) {
+	base, err := url.Parse(c.URL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// List returns the VTODOs of the collection by UID
+func (c *calDAVClient) List() (map[string]calDAVObject, error) {
+	header := http.Header{
+		"Depth":        {"1"},
+		"Content-Type": {"application/xml; charset=utf-8"},
+	}
+	resp, err := c.do("REPORT", c.URL, []byte(calDAVQuery), header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var multistatus calDAVMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&multistatus); err != nil {
+		return nil, errorf(ExitParse, "caldav: invalid response: %w", err)
+	}
+
+	objects := make(map[string]calDAVObject)
+	for _, response := range multistatus.Responses {
+		href, err := c.resolve(response.Href)
+		if err != nil {
+			return nil, errorf(ExitParse, "caldav: invalid href '%s': %w", response.Href, err)
+		}
+
+		for _, propstat := range response.Propstat {
+			if propstat.Prop.CalendarData == "" {
+				continue
+			}
+			todos, err := readVTODOs(strings.NewReader(propstat.Prop.CalendarData))
+			if err != nil {
+				return nil, err
+			}
+			for _, todo := range todos {
+				if uid := todo.Task.Metadata["uid"]; uid != "" {
+					objects[uid] = calDAVObject{Href: href, ETag: propstat.Prop.ETag, Todo: todo}
+				}
+			}
+		}
+	}
+	return objects, nil
+}
+
+// Put writes a VTODO. Without an etag the object must not exist yet, otherwise it must not have changed since.
+func (c *calDAVClient) Put(href string, todo []string, etag string) error {
+	var body bytes.Buffer
+	if err := writeVCALENDAR(&body, [][]string{todo}); err != nil {
+		return err
+	}
+
+	header := http.Header{"Content-Type": {"text/calendar; charset=utf-8"}}
+	if etag == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", etag)
+	}
+
+	resp, err := c.do(http.MethodPut, href, body.Bytes(), header)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Delete removes a VTODO, provided it has not changed since it was listed
+func (c *calDAVClient) Delete(href, etag string) error {
+	resp, err := c.do(http.MethodDelete, href, nil, http.Header{"If-Match": {etag}})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// NewHref returns the URL of a new calendar object for the UID
+func (c *calDAVClient) NewHref(uid string) string {
+	return strings.TrimSuffix(c.URL, "/") + "/" + url.PathEscape(uid) + ".ics"
+}
+
+// calDAVState remembers the tasks as they were at the end of the last sync,
+// so changes made since can be told apart on both sides
+type calDAVState struct {
+	URL   string                  `json:"url"`   // Collection the file was synced with
+	Tasks map[string]calDAVSynced `json:"tasks"` // By UID
+}
+
+// calDAVSynced is the state of a task at the end of the last sync
+type calDAVSynced struct {
+	ETag string `json:"etag"` // Etag of the object on the server
+	Hash string `json:"hash"` // Hash of the task in the markdown file, see calDAVHash
+}
+
+// calDAVStatePath returns the path of the CalDAV sync state of the markdown file
+func calDAVStatePath(filePath string) string {
+	return sidecarPath(filePath, "caldav.json")
+}
+
+// loadCalDAVState reads the sync state of the markdown file, returning an empty state if there is none
+func loadCalDAVState(filePath string) (calDAVState, error) {
+	var state calDAVState
+	err := readSyncState(calDAVStatePath(filePath), &state)
+	return state, err
+}
+
+// saveCalDAVState writes the sync state of the markdown file
+func saveCalDAVState(filePath string, state calDAVState) error {
+	return writeSyncState(calDAVStatePath(filePath), state)
+}
+
+// calDAVHash summarizes what is synced of a task, to detect local changes
+func calDAVHash(item Item, path []string) string {
+	sum := sha1.Sum([]byte(strings.Join(formatVTODO(item, path, time.Time{}), "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// syncCalDAV synchronizes the tasks of the file with the collection in both directions:
+//   - tasks changed on one side only are copied to the other, the server giving status and due date;
+//   - tasks changed on both sides keep the most recent version, local changes dating from modified;
+//   - new tasks are created on the other side, and tasks deleted on one side are deleted on the other
+//     unless they changed in the meantime.
+//
+// Tasks without id: or uid: metadata get a uid: so they are recognized once renamed.
+func syncCalDAV(tm *TaskManager, client *calDAVClient, state *calDAVState, modified time.Time) ([]syncChange, error) {
+	remote, err := client.List()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := sectionPaths(tm.Items)
+	local := make(map[string]int)
+	var uids []string
+	for i, uid := range uniqueKeys(tm.Items, icsUID, icsUIDSuffix) {
+		item := tm.Items[i]
+		if item.Type != TypeTask {
+			continue
+		}
+		// Tasks get a uid: to be matched on the next sync, unless their id: gives it,
+		// and identical tasks in the same section keep the distinct UIDs they were given
+		_, hasUID := item.Metadata["uid"]
+		_, hasID := item.Metadata["id"]
+		if (!hasUID && !hasID) || uid != icsUID(item, paths[i]) {
+			if err := tm.SetMetadata(i, map[string]string{"uid": uid}, nil); err != nil {
+				return nil, err
+			}
+		}
+		local[uid] = i
+		uids = append(uids, uid)
+	}
+	for _, uid := range slices.Sorted(maps.Keys(remote)) {
+		if _, ok := local[uid]; !ok {
+			uids = append(uids, uid)
+		}
+	}
+
+	hash := func(index int) string { return calDAVHash(tm.Items[index], paths[index]) }
+	push := func(uid string, index int, href, etag string) error {
+		todo := formatVTODO(tm.Items[index], paths[index], clock.Now())
+		todo = slices.Insert(todo, len(todo)-1, "LAST-MODIFIED:"+modified.UTC().Format("20060102T150405Z"))
+		return client.Put(href, todo, etag)
+	}
+
+	var changes []syncChange
+	var removed []int
+	var added []icsTodo
+	for _, uid := range uids {
+		index, inLocal := local[uid]
+		object, inRemote := remote[uid]
+		synced, inState := state.Tasks[uid]
+
+		localChanged := inLocal && (!inState || hash(index) != synced.Hash)
+		remoteChanged := inRemote && (!inState || object.ETag != synced.ETag)
+
+		if inLocal && inRemote && localChanged && remoteChanged {
+			// Changes of the server that don't affect the task are not conflicts
+			pulled := tm.Items[index]
+			checked := *pulled.Checked
+			pulled.Checked = &checked
+			pulled.Metadata = maps.Clone(pulled.Metadata)
+			updateICSTask(&pulled, object.Todo.Task)
+			if calDAVHash(pulled, paths[index]) == hash(index) {
+				remoteChanged = false
+				localChanged = inState
+			}
+		}
+
+		change := syncChange{Key: uid}
+		switch {
+		case inLocal && inRemote && localChanged && remoteChanged:
+			change.Action = "conflict"
+			if object.Todo.Modified.After(modified) {
+				change.Detail = "kept server version"
+				updateICSTask(&tm.Items[index], object.Todo.Task)
+			} else {
+				change.Detail = "kept local version"
+				if err := push(uid, index, object.Href, object.ETag); err != nil {
+					return nil, err
+				}
+			}
+		case inLocal && inRemote && localChanged:
+			change.Action = "pushed"
+			if err := push(uid, index, object.Href, object.ETag); err != nil {
+				return nil, err
+			}
+		case inLocal && inRemote && remoteChanged:
+			change.Action = "pulled"
+			updateICSTask(&tm.Items[index], object.Todo.Task)
+		case inLocal && inRemote:
+			continue
+		case inLocal && inState && !localChanged:
+			change.Action = "removed" // Deleted on the server
+			removed = append(removed, index)
+		case inLocal:
+			change.Action = "created"
+			if err := push(uid, index, client.NewHref(uid), ""); err != nil {
+				return nil, err
+			}
+		case inRemote && inState && !remoteChanged:
+			change.Action = "deleted" // Deleted from the file
+			if err := client.Delete(object.Href, object.ETag); err != nil {
+				return nil, err
+			}
+			change.Content = object.Todo.Task.Content
+		case inRemote:
+			change.Action = "added"
+			change.Content = object.Todo.Task.Content
+			added = append(added, object.Todo)
+		}
+
+		if inLocal {
+			change.Content = tm.Items[index].Content
+		}
+		changes = append(changes, change)
+	}
+
+	// Apply the changes to the file, removals first so indices stay valid
+	slices.Sort(removed)
+	for _, index := range slices.Backward(removed) {
+		tm.Items = deleteItem(tm.Items, index)
+	}
+	if len(added) > 0 {
+		tasks := make([]Item, len(added))
+		addedPaths := make([][]string, len(added))
+		for i, todo := range added {
+			tasks[i], addedPaths[i] = todo.Task, todo.Path
+		}
+		tm.Items = mergeItems(tm.Items, buildSections(tasks, addedPaths))
+	}
+
+	// Remember the tasks as they now are on both sides
+	remote, err = client.List()
+	if err != nil {
+		return nil, err
+	}
+	state.Tasks = make(map[string]calDAVSynced)
+	paths = sectionPaths(tm.Items)
+	for i, item := range tm.Items {
+		if item.Type != TypeTask {
+			continue
+		}
+		uid := icsUID(item, paths[i])
+		if object, ok := remote[uid]; ok {
+			state.Tasks[uid] = calDAVSynced{ETag: object.ETag, Hash: calDAVHash(item, paths[i])}
+		}
+	}
+
+	return changes, nil
+}
+
+func newSyncCalDAVCommand() *cobra.Command {
+	var (
+		collection string
+		user       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "caldav",
+		Short: "Two-way sync with a CalDAV task list",
+		Long: `Synchronize the tasks of the markdown file with the VTODOs of a CalDAV collection, in both directions.
+New and changed tasks are pushed to the server, completions and due dates changed on the server are pulled,
+and tasks deleted on one side are deleted on the other. When a task changed on both sides since the last sync,
+the most recent version wins, the modification time of the markdown file standing for local changes.
+The password is read from the TASKS_CALDAV_PASSWORD environment variable.
+The URL is remembered with the sync state (TODO.md uses TODO.caldav.json), so --url is only needed the first time.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state, err := loadCalDAVState(filePath)
+			if err != nil {
+				return err
+			}
+			if collection == "" {
+				collection = state.URL
+			}
+			if collection == "" {
+				return errorf(ExitUsage, "--url is required for the first sync")
+			}
+			if state.URL != collection {
+				state = calDAVState{URL: collection}
+			}
+
+			// Local changes date from the last write of the file
+			modified := clock.Now()
+			if info, err := os.Stat(filePath); err == nil {
+				modified = info.ModTime()
+			}
+
+			tm, err := NewTaskManager(filePath)
+			if err != nil {
+				return err
+			}
+
+			client := &calDAVClient{
+				URL:      collection,
+				User:     user,
+				Password: os.Getenv("TASKS_CALDAV_PASSWORD"),
+				HTTP:     &http.Client{Timeout: 30 * time.Second},
+			}
+			changes, err := syncCalDAV(tm, client, &state, modified)
+			if err != nil {
+				return err
+			}
+
+			if err := tm.Save(); err != nil {
+				return fmt.Errorf("saving file: %w", err)
+			}
+			if err := saveCalDAVState(filePath, state); err != nil {
+				return err
+			}
+
+			return printSyncReport(collection, changes)
+		},
+	}
+
+	cmd.Flags().StringVar(&collection, "url", "", "URL of the CalDAV collection (default: the one of the last sync)")
+	cmd.Flags().StringVar(&user, "user", "", "User name for basic authentication")
+
+	return cmd
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCalDAV is an in-memory CalDAV collection, enough for syncCalDAV
+type fakeCalDAV struct {
+	mu      sync.Mutex
+	objects map[string]fakeCalDAVObject // By path
+	version int
+}
+
+type fakeCalDAVObject struct {
+	ETag string
+	Data string
+}
+
+func newFakeCalDAV(t *testing.T) (*fakeCalDAV, *calDAVClient) {
+	fake := &fakeCalDAV{objects: make(map[string]fakeCalDAVObject)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, &calDAVClient{URL: server.URL + "/tasks/", HTTP: server.Client()}
+}
+
+// store saves an object as a client would, returning its new etag
+func (f *fakeCalDAV) store(path, data string) string {
+	f.version++
+	etag := fmt.Sprintf(`"%d"`, f.version)
+	f.objects[path] = fakeCalDAVObject{ETag: etag, Data: data}
+	return etag
+}
+
+// edit changes the object holding the UID as another client would
+func (f *fakeCalDAV) edit(t *testing.T, uid string, change func(data string) string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for path, object := range f.objects {
+		if strings.Contains(object.Data, "UID:"+uid+"\r\n") {
+			f.store(path, change(object.Data))
+			return
+		}
+	}
+	t.Fatalf("no object with UID %s", uid)
+}
+
+func (f *fakeCalDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, exists := f.objects[r.URL.Path]
+	if match := r.Header.Get("If-Match"); match != "" && (!exists || object.ETag != match) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	switch r.Method {
+	case "REPORT":
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+		for path, object := range f.objects {
+			b.WriteString("<d:response><d:href>" + path + "</d:href><d:propstat><d:prop><d:getetag>")
+			xml.EscapeText(&b, []byte(object.ETag))
+			b.WriteString("</d:getetag><c:calendar-data>")
+			xml.EscapeText(&b, []byte(object.Data))
+			b.WriteString("</c:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>")
+		}
+		b.WriteString("</d:multistatus>")
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(b.String()))
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("ETag", f.store(r.URL.Path, string(data)))
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// syncTestFile runs syncCalDAV on the markdown file and saves it
+func syncTestFile(t *testing.T, path string, client *calDAVClient, state *calDAVState, modified time.Time) []syncChange {
+	t.Helper()
+	tm, err := NewTaskManager(path)
+	require.NoError(t, err)
+	changes, err := syncCalDAV(tm, client, state, modified)
+	require.NoError(t, err)
+	require.NoError(t, tm.Save())
+	return changes
+}
+
+// syncActions returns the action and description of each change
+func syncActions(changes []syncChange) []string {
+	var actions []string
+	for _, change := range changes {
+		action := change.Action + " " + change.Content
+		if change.Detail != "" {
+			action += " (" + change.Detail + ")"
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestSyncCalDAV(t *testing.T) {
+	useClock(t, time.Date(2025, 8, 13, 15, 4, 5, 0, time.UTC))
+	fake, client := newFakeCalDAV(t)
+	modified := time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC)
+
+	fake.store("/tasks/remote.ics", strings.Join([]string{
+		"BEGIN:VCALENDAR", "VERSION:2.0",
+		"BEGIN:VTODO", "UID:remote-1@example.com", "SUMMARY:Renew passport", "CATEGORIES:Home", "END:VTODO",
+		"END:VCALENDAR", "",
+	}, "\r\n"))
+
+	path := createTestFile(t, `# Work
+- [ ] Deploy id:deploy due:2025-08-15
+- [ ] Write docs
+`)
+	state := &calDAVState{URL: client.URL}
+
+	// First sync: local tasks are created on the server, remote ones added to the file
+	changes := syncTestFile(t, path, client, state, modified)
+	require.Equal(t, []string{"created Deploy", "created Write docs", "added Renew passport"}, syncActions(changes))
+	require.Len(t, fake.objects, 3)
+	require.Len(t, state.Tasks, 3)
+	require.Contains(t, fake.objects["/tasks/deploy@tasks.ics"].Data, "CATEGORIES:Work\r\nLAST-MODIFIED:20250813T120000Z\r\n")
+
+	content := readTestFile(t, path)
+	require.Contains(t, content, "# Home\n\n- [ ] Renew passport uid:\"remote-1@example.com\"\n")
+	require.Contains(t, content, "- [ ] Write docs uid:", "Tasks without an id get a UID")
+
+	// Nothing changed
+	require.Empty(t, syncTestFile(t, path, client, state, modified))
+
+	// Completed on the server, postponed locally
+	fake.edit(t, "deploy@tasks", func(data string) string {
+		return strings.Replace(data, "STATUS:NEEDS-ACTION", "STATUS:COMPLETED\r\nCOMPLETED:20250813T130000Z", 1)
+	})
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(readTestFile(t, path), "Renew passport", "Renew passport due:2025-09-01", 1)), 0o644))
+
+	changes = syncTestFile(t, path, client, state, modified)
+	require.Equal(t, []string{"pulled Deploy", "pushed Renew passport"}, syncActions(changes))
+	require.Contains(t, readTestFile(t, path), "- [x] Deploy completed:2025-08-13 due:2025-08-15 id:deploy\n")
+	require.Contains(t, fake.objects["/tasks/remote.ics"].Data, "DUE;VALUE=DATE:20250901")
+	require.Empty(t, syncTestFile(t, path, client, state, modified))
+
+	// Changed on both sides: the most recent change wins
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(readTestFile(t, path), "- [ ] Renew passport", "- [x] Renew passport", 1)), 0o644))
+	fake.edit(t, "remote-1@example.com", func(data string) string {
+		data = strings.Replace(data, "LAST-MODIFIED:20250813T120000Z", "LAST-MODIFIED:20250813T140000Z", 1)
+		return strings.Replace(data, "DUE;VALUE=DATE:20250901", "DUE;VALUE=DATE:20251001", 1)
+	})
+
+	changes = syncTestFile(t, path, client, state, modified)
+	require.Equal(t, []string{"conflict Renew passport (kept server version)"}, syncActions(changes))
+	require.Contains(t, readTestFile(t, path), "- [ ] Renew passport due:2025-10-01")
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(readTestFile(t, path), "due:2025-10-01", "due:2025-11-01", 1)), 0o644))
+	fake.edit(t, "remote-1@example.com", func(data string) string {
+		return strings.Replace(data, "STATUS:NEEDS-ACTION", "STATUS:COMPLETED", 1)
+	})
+	changes = syncTestFile(t, path, client, state, time.Date(2025, 8, 13, 16, 0, 0, 0, time.UTC))
+	require.Equal(t, []string{"conflict Renew passport (kept local version)"}, syncActions(changes))
+	require.Contains(t, fake.objects["/tasks/remote.ics"].Data, "STATUS:NEEDS-ACTION\r\nDUE;VALUE=DATE:20251101")
+
+	// Deleted on one side
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(readTestFile(t, path), "- [x] Deploy completed:2025-08-13 due:2025-08-15 id:deploy\n", "", 1)), 0o644))
+	fake.mu.Lock()
+	delete(fake.objects, "/tasks/remote.ics")
+	fake.mu.Unlock()
+
+	changes = syncTestFile(t, path, client, state, modified)
+	require.ElementsMatch(t, []string{"deleted Deploy", "removed Renew passport"}, syncActions(changes))
+	require.Len(t, fake.objects, 1)
+	require.NotContains(t, readTestFile(t, path), "Renew passport")
+	require.Len(t, state.Tasks, 1)
+}
+
+func TestSyncCalDAV_DuplicateTasks(t *testing.T) {
+	_, client := newFakeCalDAV(t)
+	path := createTestFile(t, `- [ ] Buy milk
+- [ ] Buy milk
+`)
+	state := &calDAVState{URL: client.URL}
+
+	changes := syncTestFile(t, path, client, state, time.Now())
+	require.Len(t, changes, 2)
+	require.NotEqual(t, changes[0].Key, changes[1].Key, "Identical tasks get distinct UIDs")
+	require.Empty(t, syncTestFile(t, path, client, state, time.Now()))
+}
+
+func TestCalDAVClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	client := &calDAVClient{URL: server.URL, HTTP: server.Client()}
+
+	_, err := client.List()
+	require.ErrorContains(t, err, "401 Unauthorized")
+
+	err = client.Put(client.NewHref("a@tasks"), []string{"BEGIN:VTODO", "END:VTODO"}, `"1"`)
+	require.Equal(t, ExitConflict, exitCode(err))
+}
+
+func TestCalDAVState(t *testing.T) {
+	path := createTestFile(t, "")
+	state, err := loadCalDAVState(path)
+	require.NoError(t, err)
+	require.Equal(t, calDAVState{}, state)
+
+	state = calDAVState{URL: "https://dav.example.com/tasks/", Tasks: map[string]calDAVSynced{"a@tasks": {ETag: `"1"`, Hash: "abc"}}}
+	require.NoError(t, saveCalDAVState(path, state))
+
+	loaded, err := loadCalDAVState(path)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// FileConfig holds per-file settings stored in a sidecar file next to the markdown file
+type FileConfig struct {
+	Sort       []string `json:"sort,omitempty"`       // Sort keys applied after every add
+	WeekStart  string   `json:"week_start,omitempty"` // First day of the week, Monday when empty
+	Timestamps bool     `json:"timestamps,omitempty"` // Stamp created and completed dates on tasks
+}
+
+// sidecarPath returns the path of a file stored next to the markdown file,
+// e.g. TODO.md with suffix "tasks.json" gives TODO.tasks.json
+func sidecarPath(filePath, suffix string) string {
+	base := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	return base + "." + suffix
+}
+
+// configPath returns the path of the config file for the markdown file
+func configPath(filePath string) string {
+	return sidecarPath(filePath, "tasks.json")
+}
+
+// loadFileConfig reads the config for the markdown file, returning an empty config if there is none
+func loadFileConfig(filePath string) (FileConfig, error) {
+	var cfg FileConfig
+
+	data, err := os.ReadFile(configPath(filePath))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return cfg, nil
+	case err != nil:
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, errorf(ExitParse, "invalid config file '%s': %w", configPath(filePath), err)
+	}
+
+	return cfg, nil
+}
+
+// saveFileConfig writes the config for the markdown file
+func saveFileConfig(filePath string, cfg FileConfig) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.WriteFile(configPath(filePath), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// configKey describes a setting that can be read and changed with the config command
+type configKey struct {
+	Description string
+	Get         func(cfg *FileConfig) string
+	Set         func(cfg *FileConfig, value string) error
+}
+
+// configKeys lists every setting supported by the config command
+var configKeys = map[string]configKey{
+	"sort": {
+		Description: "Sort keys applied after every add without --after (e.g. priority,due)",
+		Get: func(cfg *FileConfig) string {
+			return strings.Join(cfg.Sort, ",")
+		},
+		Set: func(cfg *FileConfig, value string) error {
+			if value == "" {
+				cfg.Sort = nil
+				return nil
+			}
+			keys, err := parseSortKeys(value)
+			if err != nil {
+				return err
+			}
+			cfg.Sort = keys
+			return nil
+		},
+	},
+	"week-start": {
+		Description: "First day of the week for relative dates and the agenda (default monday)",
+		Get: func(cfg *FileConfig) string {
+			return cfg.WeekStart
+		},
+		Set: func(cfg *FileConfig, value string) error {
+			if _, err := parseWeekday(value); err != nil {
+				return err
+			}
+			cfg.WeekStart = strings.ToLower(value)
+			return nil
+		},
+	},
+	"timestamps": {
+		Description: "Stamp created: on add and completed: on done (true or false, default false)",
+		Get: func(cfg *FileConfig) string {
+			return strconv.FormatBool(cfg.Timestamps)
+		},
+		Set: func(cfg *FileConfig, value string) error {
+			if value == "" {
+				cfg.Timestamps = false
+				return nil
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return errorf(ExitParse, "invalid timestamps value '%s' (must be true or false)", value)
+			}
+			cfg.Timestamps = enabled
+			return nil
+		},
+	},
+}
+
+func newConfigCommand() *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "config [key] [value]",
+		Short: "Show or change per-file settings",
+		Long: `Show or change settings for the markdown file.
+Settings are stored next to the markdown file (TODO.md uses TODO.tasks.json).
+Without arguments all settings are listed, with a key its value is shown,
+and with a key and a value the setting is changed.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadFileConfig(filePath)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				for _, name := range slices.Sorted(maps.Keys(configKeys)) {
+					if porcelain != "" {
+						printPorcelain("config", name, configKeys[name].Get(&cfg))
+						continue
+					}
+					fmt.Printf("%s=%s\n", name, configKeys[name].Get(&cfg))
+				}
+				return nil
+			}
+
+			key, ok := configKeys[args[0]]
+			if !ok {
+				return errorf(ExitUsage, "unknown config key '%s'", args[0])
+			}
+
+			switch {
+			case unset:
+				if err := key.Set(&cfg, ""); err != nil {
+					return err
+				}
+			case len(args) == 2:
+				if err := key.Set(&cfg, args[1]); err != nil {
+					return err
+				}
+			default:
+				if porcelain != "" {
+					printPorcelain("config", args[0], key.Get(&cfg))
+					return nil
+				}
+				fmt.Println(key.Get(&cfg))
+				return nil
+			}
+
+			if err := saveFileConfig(filePath, cfg); err != nil {
+				return err
+			}
+
+			if porcelain != "" {
+				printPorcelain("config", args[0], key.Get(&cfg))
+				return nil
+			}
+
+			fmt.Printf("Set %s=%s\n", args[0], key.Get(&cfg))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&unset, "unset", false, "Reset the setting to its default")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var completions []string
+		for _, name := range slices.Sorted(maps.Keys(configKeys)) {
+			completions = append(completions, name+"\t"+configKeys[name].Description)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
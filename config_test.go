@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSidecarPath(t *testing.T) {
+	require.Equal(t, "TODO.tasks.json", sidecarPath("TODO.md", "tasks.json"))
+	require.Equal(t, "/tmp/notes/daily.tasks.json", sidecarPath("/tmp/notes/daily.md", "tasks.json"))
+	require.Equal(t, "list.tasks.json", sidecarPath("list", "tasks.json"))
+}
+
+func TestFileConfig_RoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "TODO.md")
+
+	// A missing config gives the defaults
+	cfg, err := loadFileConfig(filename)
+	require.NoError(t, err)
+	require.Empty(t, cfg.Sort)
+
+	cfg.Sort = []string{"priority", "due"}
+	require.NoError(t, saveFileConfig(filename, cfg))
+
+	loaded, err := loadFileConfig(filename)
+	require.NoError(t, err)
+	require.Equal(t, cfg, loaded)
+}
+
+func TestFileConfig_Invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "TODO.md")
+	require.NoError(t, os.WriteFile(configPath(filename), []byte("{not json"), 0o644))
+
+	_, err := loadFileConfig(filename)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid config file")
+
+	_, err = NewTaskManager(filename)
+	require.Error(t, err)
+}
+
+func TestConfigKeys_Sort(t *testing.T) {
+	var cfg FileConfig
+
+	require.NoError(t, configKeys["sort"].Set(&cfg, "priority,text"))
+	require.Equal(t, []string{"priority", "text"}, cfg.Sort)
+	require.Equal(t, "priority,text", configKeys["sort"].Get(&cfg))
+
+	require.Error(t, configKeys["sort"].Set(&cfg, "bad key"))
+
+	require.NoError(t, configKeys["sort"].Set(&cfg, ""))
+	require.Empty(t, cfg.Sort)
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CSV files, such as spreadsheets and issue tracker exports, are imported with a mapping from task fields
+// to the columns of the header line:
+//
+//	title=Summary,section=Epic,due=Due Date,done=Status==Closed
+//
+// title gives the description, section the section path (repeated for nested sections), done the status
+// (with ==, the values meaning done separated by |) and tags the tags. Other fields are metadata keys.
+// Without a mapping, the columns named after a field are used.
+
+// columnMapping maps task fields to the columns of a CSV file
+type columnMapping struct {
+	Title      string
+	Sections   []string
+	Done       string
+	DoneValues []string // Values of the done column meaning the task is done, the usual ones when empty
+	Tags       string
+	Metadata   map[string]string // Column of each metadata key
+}
+
+// csvDefaultColumns lists the columns used without a mapping
+var csvDefaultColumns = []string{"title", "section", "done", "tags", "due", "priority"}
+
+// csvDoneValues are the values of a done column meaning the task is done, when no value is given
+var csvDoneValues = []string{"x", "yes", "y", "true", "1", "done", "closed", "completed", "resolved"}
+
+// parseColumnMapping parses --map values: field=column pairs, done accepting field=column==value
+func parseColumnMapping(specs []string) (columnMapping, error) {
+	mapping := columnMapping{Metadata: make(map[string]string)}
+	for _, spec := range specs {
+		field, column, ok := strings.Cut(spec, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return columnMapping{}, errorf(ExitUsage, "invalid column mapping '%s' (expected field=column)", spec)
+		}
+
+		var value string
+		column, value, hasValue := strings.Cut(column, "==")
+		column = strings.TrimSpace(column)
+		if hasValue && field != "done" {
+			return columnMapping{}, errorf(ExitUsage, "invalid column mapping '%s' (only done accepts ==value)", spec)
+		}
+
+		switch field {
+		case "title":
+			mapping.Title = column
+		case "section":
+			mapping.Sections = append(mapping.Sections, column)
+		case "done":
+			mapping.Done = column
+			for value := range strings.SplitSeq(value, "|") {
+				if value = strings.TrimSpace(value); value != "" {
+					mapping.DoneValues = append(mapping.DoneValues, value)
+				}
+			}
+		case "tags":
+			mapping.Tags = column
+		default:
+			if !isIdentifier(field) {
+				return columnMapping{}, errorf(ExitUsage, "invalid column mapping '%s' (%s is not a valid metadata key)", spec, field)
+			}
+			mapping.Metadata[field] = column
+		}
+	}
+	return mapping, nil
+}
+
+// defaultColumnMapping maps the fields to the columns named after them, ignoring case
+func defaultColumnMapping(header []string) columnMapping {
+	var specs []string
+	for _, column := range header {
+		if slices.Contains(csvDefaultColumns, strings.ToLower(strings.TrimSpace(column))) {
+			specs = append(specs, strings.TrimSpace(column)+"="+column)
+		}
+	}
+	mapping, _ := parseColumnMapping(specs)
+	return mapping
+}
+
+// csvDelimiter guesses the delimiter of a CSV file from its header line: comma, semicolon or tab
+func csvDelimiter(header string) rune {
+	delimiter := ','
+	for _, candidate := range []rune{';', '\t'} {
+		if strings.Count(header, string(candidate)) > strings.Count(header, string(delimiter)) {
+			delimiter = candidate
+		}
+	}
+	return delimiter
+}
+
+// csvDate returns the date at the start of a date and time value, such as 2025-08-15 10:00 or 2025-08-15T10:00:00Z
+func csvDate(value string) string {
+	if len(value) > len(time.DateOnly) {
+		if _, err := time.Parse(time.DateOnly, value[:len(time.DateOnly)]); err == nil {
+			return value[:len(time.DateOnly)]
+		}
+	}
+	return value
+}
+
+// importCSV reads a CSV file with a header line, placing tasks under the sections given by their section columns
+func importCSV(r io.Reader, specs []string) ([]Item, error) {
+	mapping, err := parseColumnMapping(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV: %w", err)
+	}
+
+	// Spreadsheets often start UTF-8 files with a byte order mark
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	firstLine, _, _ := strings.Cut(text, "\n")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = csvDelimiter(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errorf(ExitParse, "invalid CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	if len(specs) == 0 {
+		mapping = defaultColumnMapping(rows[0])
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		name = strings.TrimSpace(name)
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+	index := func(column string) (int, error) {
+		if column == "" {
+			return -1, nil
+		}
+		i, ok := columns[column]
+		if !ok {
+			return -1, errorf(ExitUsage, "unknown column '%s' (columns: %s)", column, strings.Join(rows[0], ", "))
+		}
+		return i, nil
+	}
+
+	if mapping.Title == "" {
+		return nil, errorf(ExitUsage, "no title column (use --map title=<column>)")
+	}
+	title, err := index(mapping.Title)
+	if err != nil {
+		return nil, err
+	}
+	done, err := index(mapping.Done)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := index(mapping.Tags)
+	if err != nil {
+		return nil, err
+	}
+	var sections []int
+	for _, column := range mapping.Sections {
+		i, err := index(column)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, i)
+	}
+	metadata := make(map[string]int)
+	for key, column := range mapping.Metadata {
+		if metadata[key], err = index(column); err != nil {
+			return nil, err
+		}
+	}
+
+	dates := newDateContext(FileConfig{})
+	var tasks []Item
+	var paths [][]string
+	for _, row := range rows[1:] {
+		cell := func(i int) string {
+			if i < 0 || i >= len(row) {
+				return ""
+			}
+			return strings.Join(strings.Fields(row[i]), " ")
+		}
+
+		content, titleMetadata := parseTitle(cell(title))
+		if content == "" {
+			continue
+		}
+
+		existing := parseTags(content)
+		for _, tag := range strings.FieldsFunc(cell(tags), func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+			if tag = normalizeTag(tag); tag != "" && !slices.Contains(existing, tag) {
+				content += " " + tag
+				existing = append(existing, tag)
+			}
+		}
+
+		checked := false
+		if value := cell(done); value != "" {
+			values := mapping.DoneValues
+			if len(values) == 0 {
+				values = csvDoneValues
+			}
+			checked = slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+		}
+
+		values := make(map[string]string)
+		for key, i := range metadata {
+			if value := cell(i); value != "" {
+				values[key] = value
+			}
+		}
+		for key, value := range titleMetadata {
+			if _, ok := values[key]; !ok {
+				values[key] = value
+			}
+		}
+		for _, key := range dateKeys {
+			if value, ok := values[key]; ok {
+				values[key] = csvDate(value)
+			}
+		}
+		dates.Normalize(values)
+		if len(values) == 0 {
+			values = nil
+		}
+
+		var path []string
+		for _, i := range sections {
+			if name := cell(i); name != "" {
+				path = append(path, name)
+			}
+		}
+
+		tasks = append(tasks, Item{
+			Type:     TypeTask,
+			Content:  content,
+			Checked:  &checked,
+			Metadata: values,
+			Tags:     existing,
+		})
+		paths = append(paths, path)
+	}
+
+	return buildSections(tasks, paths), nil
+}
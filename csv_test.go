@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColumnMapping(t *testing.T) {
+	mapping, err := parseColumnMapping([]string{"title=Summary", "section=Project", "section=Epic", "due=Due Date", "done=Status==Closed|Resolved", "Tags=Labels"})
+	require.NoError(t, err)
+	require.Equal(t, columnMapping{
+		Title:      "Summary",
+		Sections:   []string{"Project", "Epic"},
+		Done:       "Status",
+		DoneValues: []string{"Closed", "Resolved"},
+		Tags:       "Labels",
+		Metadata:   map[string]string{"due": "Due Date"},
+	}, mapping)
+
+	for _, spec := range []string{"title", "title=", "=Summary", "due=Due==x", "due date=Due"} {
+		_, err := parseColumnMapping([]string{spec})
+		require.Error(t, err, spec)
+		require.Equal(t, ExitUsage, exitCode(err), spec)
+	}
+}
+
+func TestCSVDelimiter(t *testing.T) {
+	require.Equal(t, ',', csvDelimiter("title,due"))
+	require.Equal(t, ';', csvDelimiter("title;due;note, with comma"))
+	require.Equal(t, '\t', csvDelimiter("title\tdue"))
+	require.Equal(t, ',', csvDelimiter("title"))
+}
+
+func TestCSVDate(t *testing.T) {
+	require.Equal(t, "2025-08-15", csvDate("2025-08-15"))
+	require.Equal(t, "2025-08-15", csvDate("2025-08-15 10:00"))
+	require.Equal(t, "2025-08-15", csvDate("2025-08-15T10:00:00Z"))
+	require.Equal(t, "15/08/2025", csvDate("15/08/2025"))
+}
+
+func TestImportCSV(t *testing.T) {
+	useClock(t, wednesday)
+	input := "\ufeffKey,Summary,Project,Epic,Due Date,Status,Labels,Priority\n" +
+		"PRJ-1,Fix login,Web,Auth,2025-08-15 10:00,Open,\"security, urgent\",High\n" +
+		"PRJ-2,Add SSO,Web,Auth,,Resolved,,\n" +
+		"PRJ-3,Write docs,,,tomorrow,In Progress,docs,\n" +
+		"PRJ-4,,Web,Auth,,Open,,\n" +
+		"PRJ-5,Dark mode,Web,,,Closed\n"
+
+	items, err := importCSV(strings.NewReader(input), []string{
+		"title=Summary", "section=Project", "section=Epic", "due=Due Date", "done=Status==Closed|Resolved",
+		"tags=Labels", "priority=Priority", "key=Key",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Write docs #docs", "Web", "Dark mode", "Auth", "Fix login #security #urgent", "Add SSO"}, contents(items))
+	require.Equal(t, []int{0, 1, 0, 2, 0, 0}, []int{items[0].Level, items[1].Level, items[2].Level, items[3].Level, items[4].Level, items[5].Level})
+
+	require.False(t, *items[0].Checked)
+	require.Equal(t, map[string]string{"key": "PRJ-3", "due": "2025-08-14"}, items[0].Metadata, "Relative dates are resolved")
+	require.True(t, *items[2].Checked, "Rows shorter than the header are accepted")
+	require.Equal(t, map[string]string{"key": "PRJ-1", "due": "2025-08-15", "priority": "High"}, items[4].Metadata)
+	require.Equal(t, []string{"#security", "#urgent"}, items[4].Tags)
+	require.True(t, *items[5].Checked)
+}
+
+func TestImportCSV_DefaultMapping(t *testing.T) {
+	input := "Title;Section;Done;Due;Notes\nBuy milk;Home;x;2025-08-15;Semi-skimmed\nCall mom;;;;\n"
+
+	items, err := importCSV(strings.NewReader(input), nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Call mom", "Home", "Buy milk"}, contents(items))
+	require.True(t, *items[2].Checked)
+	require.Equal(t, map[string]string{"due": "2025-08-15"}, items[2].Metadata)
+	require.Nil(t, items[0].Metadata)
+}
+
+func TestImportCSV_MetadataInTitle(t *testing.T) {
+	input := "Title,Due\nRefactor key:value parser,\nShip due:2025-09-01,2025-08-20\n"
+
+	items, err := importCSV(strings.NewReader(input), nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Refactor parser", "Ship"}, contents(items))
+	require.Equal(t, map[string]string{"key": "value"}, items[0].Metadata)
+	require.Equal(t, map[string]string{"due": "2025-08-20"}, items[1].Metadata, "Columns take precedence over the title")
+}
+
+func TestImportCSV_Errors(t *testing.T) {
+	input := "Summary,Status\nFix login,Open\n"
+
+	_, err := importCSV(strings.NewReader(input), nil)
+	require.ErrorContains(t, err, "no title column")
+	require.Equal(t, ExitUsage, exitCode(err))
+
+	_, err = importCSV(strings.NewReader(input), []string{"title=Summary", "done=State"})
+	require.ErrorContains(t, err, "unknown column 'State' (columns: Summary, Status)")
+	require.Equal(t, ExitUsage, exitCode(err))
+
+	items, err := importCSV(strings.NewReader(""), []string{"title=Summary"})
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock provides the current time so date handling can be tested
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock backed by the system time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// clock is the Clock used by commands
+var clock Clock = systemClock{}
+
+// dateKeys lists the metadata keys holding dates
+var dateKeys = []string{"due", "snooze", "wait", "created", "completed"}
+
+// DateContext holds what is needed to resolve relative dates
+type DateContext struct {
+	Today     time.Time    // Current time, relative dates are resolved from its day
+	WeekStart time.Weekday // First day of the week
+}
+
+// newDateContext returns a date context for the current time using the file's week start
+func newDateContext(cfg FileConfig) DateContext {
+	weekStart, err := parseWeekday(cfg.WeekStart)
+	if err != nil {
+		weekStart = time.Monday
+	}
+	return DateContext{Today: clock.Now(), WeekStart: weekStart}
+}
+
+// parseWeekday parses a weekday name, defaulting to Monday when empty
+func parseWeekday(name string) (time.Weekday, error) {
+	if name == "" {
+		return time.Monday, nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if strings.EqualFold(name, full) || strings.EqualFold(name, full[:3]) {
+			return day, nil
+		}
+	}
+	return time.Sunday, errorf(ExitParse, "invalid weekday '%s'", name)
+}
+
+// Parse parses a date metadata value.
+// ISO dates (2006-01-02) and relative dates are recognized: today, tomorrow, yesterday,
+// weekday names (the next such day), offsets (+3d, -1d, +2w, +1m), next week and next month.
+func (dc DateContext) Parse(value string) (time.Time, bool) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, dc.Today.Location()); err == nil {
+		return t, true
+	}
+
+	today := startOfDay(dc.Today)
+	value = strings.ToLower(strings.TrimSpace(value))
+	words := strings.NewReplacer("-", " ", "_", " ").Replace(value)
+
+	switch words {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "next week":
+		return dc.StartOfNextWeek(), true
+	case "next month":
+		year, month, _ := today.Date()
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, today.Location()), true
+	}
+
+	if day, err := parseWeekday(strings.TrimPrefix(words, "next ")); err == nil && words != "" {
+		days := (int(day)-int(today.Weekday())+6)%7 + 1 // Always in the future
+		return today.AddDate(0, 0, days), true
+	}
+
+	// Offsets like +3d or -2w
+	if len(value) >= 3 && (value[0] == '+' || value[0] == '-') {
+		n, err := strconv.Atoi(value[1 : len(value)-1])
+		if err != nil {
+			return time.Time{}, false
+		}
+		if value[0] == '-' {
+			n = -n
+		}
+
+		switch value[len(value)-1] {
+		case 'd':
+			return today.AddDate(0, 0, n), true
+		case 'w':
+			return today.AddDate(0, 0, 7*n), true
+		case 'm':
+			return today.AddDate(0, n, 0), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Normalize replaces recognized relative dates in the date metadata with ISO dates
+func (dc DateContext) Normalize(metadata map[string]string) {
+	for _, key := range dateKeys {
+		value, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		if date, ok := dc.Parse(value); ok {
+			metadata[key] = date.Format(time.DateOnly)
+		}
+	}
+}
+
+// Validate returns an error for the first date metadata value that can't be read
+func (dc DateContext) Validate(metadata map[string]string) error {
+	for _, key := range dateKeys {
+		if value, ok := metadata[key]; ok {
+			if _, ok := dc.Parse(value); !ok {
+				return errorf(ExitParse, "invalid %s date '%s'", key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// StartOfNextWeek returns midnight of the first day of next week
+func (dc DateContext) StartOfNextWeek() time.Time {
+	daysLeft := 7 - (int(dc.Today.Weekday())-int(dc.WeekStart)+7)%7
+	return startOfDay(dc.Today).AddDate(0, 0, daysLeft)
+}
+
+// startOfDay truncates t to midnight in its location
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// DueStatus classifies a task by its due date
+type DueStatus int
+
+const (
+	DueNone     DueStatus = iota // No due date, or the task is completed
+	DueOverdue                   // Due before today
+	DueToday                     // Due today
+	DueThisWeek                  // Due later this week
+	DueLater                     // Due after this week
+)
+
+// String returns the agenda heading of the status
+func (s DueStatus) String() string {
+	switch s {
+	case DueOverdue:
+		return "Overdue"
+	case DueToday:
+		return "Today"
+	case DueThisWeek:
+		return "This week"
+	case DueLater:
+		return "Later"
+	default:
+		return "No due date"
+	}
+}
+
+// DueStatus classifies an open task by its due metadata
+func (dc DateContext) DueStatus(item Item) DueStatus {
+	if item.Type != TypeTask || (item.Checked != nil && *item.Checked) {
+		return DueNone
+	}
+
+	due, ok := dc.Parse(item.Metadata["due"])
+	if !ok {
+		return DueNone
+	}
+
+	switch day := startOfDay(dc.Today); {
+	case due.Before(day):
+		return DueOverdue
+	case due.Equal(day):
+		return DueToday
+	case due.Before(dc.StartOfNextWeek()):
+		return DueThisWeek
+	default:
+		return DueLater
+	}
+}
+
+// IsOverdue reports whether the item is an open task whose due date is before today
+func (dc DateContext) IsOverdue(item Item) bool {
+	return dc.DueStatus(item) == DueOverdue
+}
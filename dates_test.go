@@ -0,0 +1,149 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock is a Clock always returning the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// useClock replaces the clock used by commands for the duration of the test
+func useClock(t *testing.T, now time.Time) {
+	t.Helper()
+
+	oldClock := clock
+	clock = fixedClock(now)
+	t.Cleanup(func() { clock = oldClock })
+}
+
+// wednesday is the reference time used by date tests
+var wednesday = time.Date(2025, 8, 13, 15, 4, 5, 0, time.Local)
+
+func TestParseWeekday(t *testing.T) {
+	day, err := parseWeekday("")
+	require.NoError(t, err)
+	require.Equal(t, time.Monday, day)
+
+	day, err = parseWeekday("Sunday")
+	require.NoError(t, err)
+	require.Equal(t, time.Sunday, day)
+
+	day, err = parseWeekday("fri")
+	require.NoError(t, err)
+	require.Equal(t, time.Friday, day)
+
+	_, err = parseWeekday("someday")
+	require.Error(t, err)
+}
+
+func TestDateContext_Parse(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.Local) }
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2025-08-10", day(8, 10)},
+		{"today", day(8, 13)},
+		{"Tomorrow", day(8, 14)},
+		{"yesterday", day(8, 12)},
+		{"+3d", day(8, 16)},
+		{"-2d", day(8, 11)},
+		{"+2w", day(8, 27)},
+		{"+1m", day(9, 13)},
+		{"friday", day(8, 15)},
+		{"fri", day(8, 15)},
+		{"wednesday", day(8, 20)},
+		{"next monday", day(8, 18)},
+		{"next week", day(8, 18)},
+		{"next-month", day(9, 1)},
+	}
+
+	for _, tc := range testCases {
+		date, ok := dates.Parse(tc.input)
+		require.True(t, ok, "Input: %s", tc.input)
+		require.Equal(t, tc.expected, date, "Input: %s", tc.input)
+	}
+
+	for _, input := range []string{"someday", "", "next", "+d", "+3x", "2025-13-01"} {
+		_, ok := dates.Parse(input)
+		require.False(t, ok, "Input: %s", input)
+	}
+}
+
+func TestDateContext_Normalize(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+
+	metadata := map[string]string{"due": "friday", "project": "tomorrow"}
+	dates.Normalize(metadata)
+	require.Equal(t, "2025-08-15", metadata["due"])
+	require.Equal(t, "tomorrow", metadata["project"], "Only date keys are normalized")
+
+	metadata = map[string]string{"due": "whenever"}
+	dates.Normalize(metadata)
+	require.Equal(t, "whenever", metadata["due"], "Unrecognized dates are kept as is")
+}
+
+func TestDateContext_Validate(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+
+	require.NoError(t, dates.Validate(map[string]string{"due": "friday", "created": "2025-08-01", "project": "whenever"}))
+	require.NoError(t, dates.Validate(nil))
+
+	err := dates.Validate(map[string]string{"due": "notadate"})
+	require.EqualError(t, err, "invalid due date 'notadate'")
+	require.Equal(t, ExitParse, exitCode(err))
+}
+
+func TestDateContext_StartOfNextWeek(t *testing.T) {
+	at := func(d int) DateContext {
+		return DateContext{Today: time.Date(2025, 8, d, 9, 0, 0, 0, time.Local), WeekStart: time.Monday}
+	}
+	monday := time.Date(2025, 8, 18, 0, 0, 0, 0, time.Local)
+
+	require.Equal(t, monday, at(11).StartOfNextWeek(), "From a Monday")
+	require.Equal(t, monday, at(13).StartOfNextWeek(), "From a Wednesday")
+	require.Equal(t, monday, at(17).StartOfNextWeek(), "From a Sunday")
+
+	sundayStart := DateContext{Today: wednesday, WeekStart: time.Sunday}
+	require.Equal(t, time.Date(2025, 8, 17, 0, 0, 0, 0, time.Local), sundayStart.StartOfNextWeek())
+	next, ok := sundayStart.Parse("next week")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2025, 8, 17, 0, 0, 0, 0, time.Local), next)
+}
+
+func TestDateContext_DueStatus(t *testing.T) {
+	dates := DateContext{Today: time.Date(2025, 8, 13, 18, 30, 0, 0, time.Local), WeekStart: time.Monday}
+	task := func(checked bool, due string) Item {
+		return Item{Type: TypeTask, Checked: &checked, Metadata: map[string]string{"due": due}}
+	}
+
+	require.Equal(t, DueOverdue, dates.DueStatus(task(false, "2025-08-12")))
+	require.Equal(t, DueToday, dates.DueStatus(task(false, "2025-08-13")))
+	require.Equal(t, DueToday, dates.DueStatus(task(false, "today")))
+	require.Equal(t, DueThisWeek, dates.DueStatus(task(false, "2025-08-17")))
+	require.Equal(t, DueLater, dates.DueStatus(task(false, "2025-08-18")))
+	require.Equal(t, DueNone, dates.DueStatus(task(true, "2025-08-01")), "Completed tasks have no due status")
+	require.Equal(t, DueNone, dates.DueStatus(task(false, "someday")))
+	require.Equal(t, DueNone, dates.DueStatus(Item{Type: TypeSection}))
+
+	require.True(t, dates.IsOverdue(task(false, "yesterday")))
+	require.False(t, dates.IsOverdue(task(false, "today")))
+}
+
+func TestNewDateContext(t *testing.T) {
+	useClock(t, wednesday)
+
+	dates := newDateContext(FileConfig{WeekStart: "sunday"})
+	require.Equal(t, wednesday, dates.Today)
+	require.Equal(t, time.Sunday, dates.WeekStart)
+
+	dates = newDateContext(FileConfig{})
+	require.Equal(t, time.Monday, dates.WeekStart)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Tasks reference each other by their stable ID, the value of their "id" metadata.
+// "after:<id>" makes a task wait on another one and "blocks:<id>" makes another task wait on it.
+// Several IDs can be given as a comma-separated quoted list, e.g. after:"build,test".
+
+// splitIDs splits a comma-separated list of stable IDs
+func splitIDs(value string) []string {
+	var ids []string
+	for id := range strings.SplitSeq(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// DependencyGraph holds the dependencies between tasks
+type DependencyGraph struct {
+	Prerequisites map[int][]int    // Task index -> indices of the tasks it waits on
+	Unknown       map[int][]string // Task index -> referenced IDs no task has
+}
+
+// buildDependencyGraph builds the dependency graph from the after and blocks metadata
+func buildDependencyGraph(items []Item) DependencyGraph {
+	graph := DependencyGraph{
+		Prerequisites: make(map[int][]int),
+		Unknown:       make(map[int][]string),
+	}
+
+	ids := make(map[string]int)
+	for i, item := range items {
+		if id, ok := item.Metadata["id"]; ok && item.Type == TypeTask {
+			if _, exists := ids[id]; !exists {
+				ids[id] = i
+			}
+		}
+	}
+
+	addEdge := func(task, prerequisite int) {
+		if task != prerequisite && !slices.Contains(graph.Prerequisites[task], prerequisite) {
+			graph.Prerequisites[task] = append(graph.Prerequisites[task], prerequisite)
+		}
+	}
+
+	for i, item := range items {
+		if item.Type != TypeTask {
+			continue
+		}
+
+		for _, id := range splitIDs(item.Metadata["after"]) {
+			if prerequisite, ok := ids[id]; ok {
+				addEdge(i, prerequisite)
+			} else {
+				graph.Unknown[i] = append(graph.Unknown[i], id)
+			}
+		}
+
+		for _, id := range splitIDs(item.Metadata["blocks"]) {
+			if task, ok := ids[id]; ok {
+				addEdge(task, i)
+			} else {
+				graph.Unknown[i] = append(graph.Unknown[i], id)
+			}
+		}
+	}
+
+	for _, prerequisites := range graph.Prerequisites {
+		slices.Sort(prerequisites)
+	}
+
+	return graph
+}
+
+// OpenPrerequisites returns the indices of the prerequisites of the task that are still open
+func (g DependencyGraph) OpenPrerequisites(items []Item, index int) []int {
+	var open []int
+	for _, prerequisite := range g.Prerequisites[index] {
+		if checked := items[prerequisite].Checked; checked == nil || !*checked {
+			open = append(open, prerequisite)
+		}
+	}
+	return open
+}
+
+// FindCycle returns the indices of tasks forming a dependency cycle, or nil if there is none.
+// The first task is repeated at the end, e.g. [a b a].
+func (g DependencyGraph) FindCycle() []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int]int)
+	var stack []int
+
+	var visit func(task int) []int
+	visit = func(task int) []int {
+		state[task] = visiting
+		stack = append(stack, task)
+
+		for _, prerequisite := range g.Prerequisites[task] {
+			switch state[prerequisite] {
+			case visiting:
+				start := slices.Index(stack, prerequisite)
+				return append(slices.Clone(stack[start:]), prerequisite)
+			case unvisited:
+				if cycle := visit(prerequisite); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[task] = visited
+		return nil
+	}
+
+	tasks := make([]int, 0, len(g.Prerequisites))
+	for task := range g.Prerequisites {
+		tasks = append(tasks, task)
+	}
+	slices.Sort(tasks)
+
+	for _, task := range tasks {
+		if state[task] == unvisited {
+			if cycle := visit(task); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// FindCycleThrough returns the indices of tasks forming a dependency cycle that goes through the task,
+// starting and ending with it, or nil if there is none
+func (g DependencyGraph) FindCycleThrough(task int) []int {
+	visited := make(map[int]bool)
+	var path []int
+
+	var visit func(current int) []int
+	visit = func(current int) []int {
+		visited[current] = true
+		path = append(path, current)
+
+		for _, prerequisite := range g.Prerequisites[current] {
+			if prerequisite == task {
+				return append(slices.Clone(path), task)
+			}
+			if !visited[prerequisite] {
+				if cycle := visit(prerequisite); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		return nil
+	}
+
+	return visit(task)
+}
+
+// checkDependencyCycle returns an error describing a dependency cycle through the task at index, if any.
+// Cycles elsewhere in the file are left alone, so that they don't block unrelated changes.
+func checkDependencyCycle(items []Item, index int) error {
+	cycle := buildDependencyGraph(items).FindCycleThrough(index)
+	if cycle == nil {
+		return nil
+	}
+	return itemErrorf(ExitConflict, cycle[0]+1, "dependency cycle: %s", formatDependencyPath(items, cycle))
+}
+
+// formatDependencyPath formats tasks as "build -> test -> build" using their stable IDs
+func formatDependencyPath(items []Item, indices []int) string {
+	parts := make([]string, len(indices))
+	for i, index := range indices {
+		parts[i] = dependencyLabel(items, index)
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// dependencyLabel returns the stable ID of a task, or its 1-based ID if it has none
+func dependencyLabel(items []Item, index int) string {
+	if id, ok := items[index].Metadata["id"]; ok {
+		return id
+	}
+	return fmt.Sprintf("%d", index+1)
+}
+
+// resolveDependencies sets BlockedBy on every task waiting on open tasks
+func resolveDependencies(items []Item) {
+	graph := buildDependencyGraph(items)
+	for i := range items {
+		items[i].BlockedBy = nil
+		for _, prerequisite := range graph.OpenPrerequisites(items, i) {
+			items[i].BlockedBy = append(items[i].BlockedBy, dependencyLabel(items, prerequisite))
+		}
+	}
+}
+
+func newBlockedCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "blocked",
+		Short: "List tasks waiting on other tasks",
+		Long: `List open tasks whose prerequisites are still open, with what they are waiting on.
+Tasks get a stable ID with id:<name> metadata; after:<id> makes a task wait on another
+and blocks:<id> makes another task wait on it. Dependency cycles and unknown IDs are reported too.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := parseMarkdownFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			graph := buildDependencyGraph(items)
+
+			found := false
+			for i, item := range items {
+				if item.Type != TypeTask || (item.Checked != nil && *item.Checked) {
+					continue
+				}
+
+				prerequisites := graph.OpenPrerequisites(items, i)
+				if len(prerequisites) == 0 {
+					continue
+				}
+
+				found = true
+				if porcelain != "" {
+					for _, prerequisite := range prerequisites {
+						printPorcelain("blocked", i+1, prerequisite+1)
+					}
+					continue
+				}
+				fmt.Println(formatItem(item, i))
+				for _, prerequisite := range prerequisites {
+					fmt.Printf("      waiting on %s\n", strings.TrimSpace(formatItem(items[prerequisite], prerequisite)))
+				}
+			}
+
+			for _, i := range slices.Sorted(maps.Keys(graph.Unknown)) {
+				fmt.Fprintf(os.Stderr, "Warning: task %d references unknown ID(s): %s\n", i+1, strings.Join(graph.Unknown[i], ", "))
+			}
+
+			if cycle := graph.FindCycle(); cycle != nil {
+				fmt.Fprintf(os.Stderr, "Warning: dependency cycle: %s\n", formatDependencyPath(items, cycle))
+			}
+
+			if !found && porcelain == "" {
+				fmt.Println("No blocked tasks")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const dependencyContent = `# Release
+- [x] Build id:build
+- [ ] Test id:test after:build
+- [ ] Deploy id:deploy after:"build,test"
+- [ ] Announce blocks:unknown
+- [ ] Write notes blocks:deploy
+`
+
+func TestSplitIDs(t *testing.T) {
+	require.Equal(t, []string{"build", "test"}, splitIDs("build, test,"))
+	require.Empty(t, splitIDs(""))
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	filename := createTestFile(t, dependencyContent)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	graph := buildDependencyGraph(items)
+	require.Equal(t, map[int][]int{
+		2: {1},
+		3: {1, 2, 5},
+	}, graph.Prerequisites)
+	require.Equal(t, map[int][]string{4: {"unknown"}}, graph.Unknown)
+
+	// Completed prerequisites don't block
+	require.Empty(t, graph.OpenPrerequisites(items, 2))
+	require.Equal(t, []int{2, 5}, graph.OpenPrerequisites(items, 3))
+	require.Nil(t, graph.FindCycle())
+}
+
+func TestResolveDependencies(t *testing.T) {
+	filename := createTestFile(t, dependencyContent)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	require.Empty(t, items[2].BlockedBy)
+	require.Equal(t, []string{"test", "6"}, items[3].BlockedBy, "Tasks without a stable ID are shown by their ID")
+	require.Empty(t, items[4].BlockedBy)
+
+	require.Contains(t, formatItem(items[3], 3), "(blocked by test, 6)")
+	require.NotContains(t, formatItem(items[2], 2), "blocked")
+}
+
+func TestDependencyGraph_FindCycle(t *testing.T) {
+	content := `- [ ] A id:a after:c
+- [ ] B id:b after:a
+- [ ] C id:c after:b
+- [ ] D id:d after:a
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	require.Equal(t, []int{0, 2, 1, 0}, buildDependencyGraph(items).FindCycle())
+
+	err = checkDependencyCycle(items, 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dependency cycle: a -> c -> b -> a")
+
+	err = checkDependencyCycle(items, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dependency cycle: b -> a -> c -> b")
+
+	require.NoError(t, checkDependencyCycle(items, 3), "D waits on the cycle but isn't part of it")
+
+	// Breaking the cycle
+	delete(items[0].Metadata, "after")
+	require.NoError(t, checkDependencyCycle(items, 0))
+}
+
+func TestDependencyGraph_SelfReference(t *testing.T) {
+	filename := createTestFile(t, "- [ ] A id:a after:a\n")
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	require.Nil(t, buildDependencyGraph(items).FindCycle())
+	require.Empty(t, items[0].BlockedBy)
+}
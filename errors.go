@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitCode is the status the tasks command exits with, so scripts can tell failures apart
+type ExitCode int
+
+const (
+	ExitOK        ExitCode = iota // Success
+	ExitError                     // Any other failure (I/O errors, cancelled removal...)
+	ExitUsage                     // Unknown command, wrong number of arguments or invalid flags
+	ExitNotFound                  // No item has the given ID
+	ExitWrongType                 // The item is a section where a task is expected, or the other way round
+	ExitConflict                  // The change conflicts with the file: blocked task, dependency cycle...
+	ExitParse                     // Invalid ID, date, metadata, recur rule or file content
+)
+
+// exitCodeNames names the exit codes in JSON errors
+var exitCodeNames = map[ExitCode]string{
+	ExitOK:        "ok",
+	ExitError:     "error",
+	ExitUsage:     "usage",
+	ExitNotFound:  "not_found",
+	ExitWrongType: "wrong_type",
+	ExitConflict:  "conflict",
+	ExitParse:     "parse",
+}
+
+// String returns the name of the exit code used in JSON errors
+func (c ExitCode) String() string {
+	if name, ok := exitCodeNames[c]; ok {
+		return name
+	}
+	return "error"
+}
+
+// codedError gives an error an exit code, and the item it is about, without changing its message
+type codedError struct {
+	code ExitCode
+	id   int // 1-based ID of the offending item, 0 if none
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withCode attaches an exit code to an error
+func withCode(code ExitCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// errorf formats an error with an exit code
+func errorf(code ExitCode, format string, args ...any) error {
+	return withCode(code, fmt.Errorf(format, args...))
+}
+
+// itemErrorf formats an error about the item with the given 1-based ID
+func itemErrorf(code ExitCode, id int, format string, args ...any) error {
+	return &codedError{code: code, id: id, err: fmt.Errorf(format, args...)}
+}
+
+// exitCode returns the exit code for an error returned by run
+func exitCode(err error) ExitCode {
+	if err == nil {
+		return ExitOK
+	}
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return ExitError
+}
+
+// errorItemID returns the 1-based ID of the item an error is about, 0 if none
+func errorItemID(err error) int {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.id
+	}
+	return 0
+}
+
+// fileError records the markdown file an error is about, when it is not the one given by --file
+type fileError struct {
+	path string
+	err  error
+}
+
+func (e *fileError) Error() string { return e.err.Error() }
+func (e *fileError) Unwrap() error { return e.err }
+
+// withFile attaches the path of the markdown file it is about to an error
+func withFile(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &fileError{path: path, err: err}
+}
+
+// errorFile returns the path of the markdown file an error is about
+func errorFile(err error) string {
+	var file *fileError
+	if errors.As(err, &file) {
+		return file.path
+	}
+	return filePath
+}
+
+// ErrorRecord is the JSON form of an error, printed on stderr with --format json or --porcelain
+type ErrorRecord struct {
+	Code     string `json:"code"` // Name of the exit code, such as "not_found"
+	ExitCode int    `json:"exit_code"`
+	Message  string `json:"message"`
+	ID       int    `json:"id,omitempty"` // 1-based ID of the offending item
+	File     string `json:"file"`
+}
+
+// newErrorRecord builds the record of an error
+func newErrorRecord(err error) ErrorRecord {
+	code := exitCode(err)
+	return ErrorRecord{
+		Code:     code.String(),
+		ExitCode: int(code),
+		Message:  err.Error(),
+		ID:       errorItemID(err),
+		File:     errorFile(err),
+	}
+}
+
+// jsonErrors is set by run when errors must be printed as JSON
+var jsonErrors bool
+
+// printError prints an error returned by run, as a JSON object if requested
+func printError(w io.Writer, err error) {
+	if jsonErrors {
+		json.NewEncoder(w).Encode(newErrorRecord(err))
+		return
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+}
+
+// wantsJSONErrors reports whether the command line of cmd asks for machine-readable output
+func wantsJSONErrors(cmd *cobra.Command) bool {
+	if porcelain != "" {
+		return true
+	}
+	if flag := cmd.Flags().Lookup("format"); flag != nil && (flag.Value.String() == "json" || flag.Value.String() == "jsonl") {
+		return true
+	}
+	flag := cmd.Flags().Lookup("json")
+	return flag != nil && flag.Value.String() == "true"
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	require.Equal(t, ExitOK, exitCode(nil))
+	require.Equal(t, ExitError, exitCode(errors.New("boom")))
+
+	err := errorf(ExitNotFound, "invalid item index: %d", 4)
+	require.EqualError(t, err, "invalid item index: 4", "The message is kept as is")
+	require.Equal(t, ExitNotFound, exitCode(err))
+
+	wrapped := fmt.Errorf("error loading file: %w", err)
+	require.Equal(t, ExitNotFound, exitCode(wrapped), "Codes survive wrapping")
+
+	require.NoError(t, withCode(ExitParse, nil))
+}
+
+func TestExitCode_Errors(t *testing.T) {
+	content := `# Project
+- [ ] Task
+`
+	tm := &TaskManager{FilePath: createTestFile(t, content)}
+	require.NoError(t, tm.Load())
+
+	_, err := tm.GetItem(5)
+	require.Equal(t, ExitNotFound, exitCode(err))
+
+	err = tm.ToggleTask(0, true)
+	require.Equal(t, ExitWrongType, exitCode(err))
+
+	err = tm.SetMetadata(0, map[string]string{"due": "today"}, nil)
+	require.Equal(t, ExitWrongType, exitCode(err))
+
+	_, err = parseItemID("abc")
+	require.Equal(t, ExitParse, exitCode(err))
+
+	_, err = parseRecurRule("sometimes")
+	require.Equal(t, ExitParse, exitCode(err))
+
+	tm.Items = append(tm.Items,
+		Item{Type: TypeTask, Checked: new(bool), Metadata: map[string]string{"id": "a", "after": "b"}},
+		Item{Type: TypeTask, Checked: new(bool), Metadata: map[string]string{"id": "b", "after": "a"}},
+	)
+	require.Equal(t, ExitConflict, exitCode(checkDependencyCycle(tm.Items, len(tm.Items)-1)))
+}
+
+func TestNewErrorRecord(t *testing.T) {
+	oldFilePath := filePath
+	filePath = "work.md"
+	t.Cleanup(func() { filePath = oldFilePath })
+
+	err := fmt.Errorf("error loading file: %w", itemErrorf(ExitWrongType, 3, "item at index %d is not a task", 2))
+	require.Equal(t, ErrorRecord{
+		Code:     "wrong_type",
+		ExitCode: 4,
+		Message:  "error loading file: item at index 2 is not a task",
+		ID:       3,
+		File:     "work.md",
+	}, newErrorRecord(err))
+
+	err = withFile("other.md", errorf(ExitParse, "invalid config file"))
+	require.Equal(t, ErrorRecord{Code: "parse", ExitCode: 6, Message: "invalid config file", File: "other.md"}, newErrorRecord(err))
+
+	require.Equal(t, ErrorRecord{Code: "error", ExitCode: 1, Message: "boom", File: "work.md"}, newErrorRecord(errors.New("boom")))
+}
+
+func TestPrintError(t *testing.T) {
+	oldFilePath := filePath
+	filePath = "TODO.md"
+	t.Cleanup(func() { filePath, jsonErrors = oldFilePath, false })
+	err := itemErrorf(ExitNotFound, 9, "invalid item index: %d", 8)
+
+	var buf bytes.Buffer
+	printError(&buf, err)
+	require.Equal(t, "Error: invalid item index: 8\n", buf.String())
+
+	jsonErrors = true
+	buf.Reset()
+	printError(&buf, err)
+	require.JSONEq(t, `{"code":"not_found","exit_code":3,"message":"invalid item index: 8","id":9,"file":"TODO.md"}`, buf.String())
+}
+
+func TestWantsJSONErrors(t *testing.T) {
+	t.Cleanup(func() { porcelain = "" })
+
+	cmd := newListCommand()
+	require.False(t, wantsJSONErrors(cmd))
+
+	require.NoError(t, cmd.Flags().Set("format", "json"))
+	require.True(t, wantsJSONErrors(cmd))
+
+	require.NoError(t, cmd.Flags().Set("format", "tsv"))
+	require.False(t, wantsJSONErrors(cmd))
+
+	porcelain = "v1"
+	require.True(t, wantsJSONErrors(cmd))
+	porcelain = ""
+
+	cmd = newStatsCommand()
+	require.NoError(t, cmd.Flags().Set("json", "true"))
+	require.True(t, wantsJSONErrors(cmd))
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// taskFormat converts tasks from and to another task list format.
+// Import returns sections and tasks in file order, with tasks placed under the sections they belong to.
+// A format supporting only one direction leaves the other function nil.
+type taskFormat struct {
+	Description string
+	Import      func(r io.Reader) ([]Item, error)
+	Export      func(w io.Writer, items []Item) error
+
+	// Formats whose columns are mapped to task fields (import --map) set ImportMapped instead of Import.
+	ImportMapped func(r io.Reader, mapping []string) ([]Item, error)
+
+	// Formats with stable task identifiers set Key to identify a task from its section path.
+	// Imported tasks with the key of an existing task update it with Update instead of being added.
+	// Suffix, if set, derives the keys of identical tasks from the key of the first one (see uniqueKeys).
+	Key    func(item Item, path []string) string
+	Suffix func(key string, n int) string
+	Update func(existing *Item, imported Item)
+}
+
+// taskFormats lists the formats supported by the import and export commands
+var taskFormats = map[string]taskFormat{
+	"csv": {
+		Description:  "CSV with a header line (spreadsheets, tracker exports), columns mapped to fields with --map",
+		ImportMapped: importCSV,
+	},
+	"ics": {
+		Description: "iCalendar VTODO (for calendar apps, CATEGORIES hold the section path and tags)",
+		Import:      importICS,
+		Export:      exportICS,
+		Key:         icsUID,
+		Suffix:      icsUIDSuffix,
+		Update:      updateICSTask,
+	},
+	"org": {
+		Description: "Org-mode (TODO/DONE headlines and checkboxes, headlines without keyword become sections)",
+		Import:      importOrg,
+		Export:      exportOrg,
+	},
+	"taskwarrior": {
+		Description: "Taskwarrior JSON (task export / task import, the project becomes the section path)",
+		Import:      importTaskwarrior,
+		Export:      exportTaskwarrior,
+		Key:         taskwarriorUUID,
+		Suffix:      taskwarriorUUIDSuffix,
+		Update:      updateTaskwarriorTask,
+	},
+	"todotxt": {
+		Description: "todo.txt (one task per line, +project becomes the section)",
+		Import:      importTodoTxt,
+		Export:      exportTodoTxt,
+	},
+}
+
+// formatNames returns the sorted names of the formats supporting import or export
+func formatNames(canImport bool) []string {
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(taskFormats)) {
+		format := taskFormats[name]
+		if (canImport && (format.Import != nil || format.ImportMapped != nil)) || (!canImport && format.Export != nil) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// lookupFormat returns the named format, checking it supports the direction
+func lookupFormat(name string, canImport bool) (taskFormat, error) {
+	names := formatNames(canImport)
+	if !slices.Contains(names, name) {
+		verb := "export"
+		if canImport {
+			verb = "import"
+		}
+		return taskFormat{}, errorf(ExitUsage, "unsupported %s format '%s' (must be one of %s)", verb, name, strings.Join(names, ", "))
+	}
+	return taskFormats[name], nil
+}
+
+// maxSectionLevel is the deepest markdown heading level
+const maxSectionLevel = 6
+
+// buildSections places tasks under the sections given by their paths, creating the sections
+// in order of first appearance. Tasks without a path come first, and sections nested deeper
+// than markdown headings allow are flattened into the deepest level.
+func buildSections(tasks []Item, paths [][]string) []Item {
+	type section struct {
+		name     string
+		tasks    []Item
+		children []*section
+	}
+
+	root := &section{}
+	for i, task := range tasks {
+		path := paths[i]
+		if len(path) > maxSectionLevel {
+			path = append(slices.Clone(path[:maxSectionLevel-1]), strings.Join(path[maxSectionLevel-1:], " / "))
+		}
+
+		node := root
+		for _, name := range path {
+			index := slices.IndexFunc(node.children, func(child *section) bool { return child.name == name })
+			if index < 0 {
+				node.children = append(node.children, &section{name: name})
+				index = len(node.children) - 1
+			}
+			node = node.children[index]
+		}
+		node.tasks = append(node.tasks, task)
+	}
+
+	var items []Item
+	var walk func(node *section, level int)
+	walk = func(node *section, level int) {
+		items = append(items, node.tasks...)
+		for _, child := range node.children {
+			items = append(items, Item{Type: TypeSection, Level: level + 1, Content: child.name})
+			walk(child, level+1)
+		}
+	}
+	walk(root, 0)
+
+	return items
+}
+
+// uniqueKeys returns the key of every task of items, sections getting an empty key.
+// Tasks having the key of an earlier task, such as identical tasks in the same section,
+// get the key given by suffix for n = 2, 3... instead; without suffix, keys are kept as is.
+func uniqueKeys(items []Item, key func(item Item, path []string) string, suffix func(key string, n int) string) []string {
+	keys := make([]string, len(items))
+	taken := make(map[string]bool)
+	for i, path := range sectionPaths(items) {
+		if items[i].Type != TypeTask {
+			continue
+		}
+		base := key(items[i], path)
+		keys[i] = base
+		for n := 2; suffix != nil && taken[keys[i]]; n++ {
+			keys[i] = suffix(base, n)
+		}
+		taken[keys[i]] = true
+	}
+	return keys
+}
+
+// updateItems updates the existing tasks having the key of an imported task, as given by format.Key,
+// and returns the imported items left to add, without the sections left empty, along with the number of updated tasks
+func updateItems(existing, imported []Item, format taskFormat) ([]Item, int) {
+	if format.Key == nil {
+		return imported, 0
+	}
+
+	keys := make(map[string]int)
+	for i, key := range uniqueKeys(existing, format.Key, format.Suffix) {
+		if existing[i].Type == TypeTask {
+			keys[key] = i
+		}
+	}
+
+	updated := 0
+	var remaining []Item
+	for i, key := range uniqueKeys(imported, format.Key, format.Suffix) {
+		item := imported[i]
+		if item.Type == TypeTask {
+			if index, ok := keys[key]; ok {
+				format.Update(&existing[index], item)
+				updated++
+				continue
+			}
+		}
+		remaining = append(remaining, item)
+	}
+
+	// Drop the sections that no longer contain any task
+	for i := len(remaining) - 1; i >= 0; i-- {
+		if remaining[i].Type != TypeSection {
+			continue
+		}
+		end := sectionEnd(remaining, i)
+		if !slices.ContainsFunc(remaining[i+1:end], func(item Item) bool { return item.Type == TypeTask }) {
+			remaining = slices.Delete(remaining, i, end)
+		}
+	}
+
+	return remaining, updated
+}
+
+// mergeItems adds imported items to the existing ones and returns the result.
+// Imported tasks outside any section go after the existing tasks before the first section.
+// Tasks of an imported section go after the direct tasks of the existing section with the same path;
+// sections that don't exist yet are added at the end of their parent section, or at the end of the file.
+func mergeItems(existing, imported []Item) []Item {
+	items := slices.Clone(existing)
+	importedPaths := sectionPaths(imported)
+
+	// directEnd returns the index just past the tasks directly following the section at index (-1 for the top level)
+	directEnd := func(index int) int {
+		end := index + 1
+		for end < len(items) && items[end].Type == TypeTask {
+			end++
+		}
+		return end
+	}
+
+	// findSection returns the index of the existing section with the path, or -1
+	findSection := func(path []string) int {
+		paths := sectionPaths(items)
+		for i, item := range items {
+			if item.Type == TypeSection && slices.Equal(paths[i], path) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	section := -1
+	for i := 0; i < len(imported); {
+		// Gather the tasks directly following the section, or at the top level
+		start := i
+		if imported[i].Type == TypeSection {
+			start++
+		}
+		end := start
+		for end < len(imported) && imported[end].Type == TypeTask {
+			end++
+		}
+		tasks := imported[start:end]
+
+		var pos int
+		if imported[i].Type == TypeSection {
+			path := importedPaths[i]
+			section = findSection(path)
+			if section < 0 {
+				// Add the missing section at the end of its parent, or of the file
+				pos = len(items)
+				if parent := findSection(path[:len(path)-1]); len(path) > 1 && parent >= 0 {
+					pos = sectionEnd(items, parent)
+				}
+				items = slices.Insert(items, pos, imported[i])
+				section = pos
+			}
+			pos = directEnd(section)
+		} else {
+			pos = directEnd(-1)
+		}
+
+		items = slices.Insert(items, pos, tasks...)
+		i = end
+	}
+
+	return items
+}
+
+func newImportCommand() *cobra.Command {
+	var (
+		from    string
+		mapping []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import [source]",
+		Short: "Import tasks from another format",
+		Long: `Import tasks from another task list format into the markdown file.
+Tasks are read from the source file, or from standard input when no source or "-" is given.
+Imported sections are merged into existing sections with the same name.
+Formats with stable task identifiers (ics, taskwarrior) update the tasks imported before instead of adding them again.
+CSV columns are mapped to task fields with --map field=column pairs: title, section (repeat it for nested sections),
+done (done=Status==Closed|Resolved tells which values mean done), tags, or any metadata key such as due.
+Without --map, the columns named after a field are used.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := lookupFormat(from, true)
+			if err != nil {
+				return err
+			}
+			if len(mapping) > 0 && format.ImportMapped == nil {
+				return errorf(ExitUsage, "--map is not supported by the %s format", from)
+			}
+
+			var r io.Reader = os.Stdin
+			if len(args) > 0 && args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to open source: %w", err)
+				}
+				defer file.Close()
+				r = file
+			}
+
+			var imported []Item
+			if format.ImportMapped != nil {
+				imported, err = format.ImportMapped(r, mapping)
+			} else {
+				imported, err = format.Import(r)
+			}
+			if err != nil {
+				return fmt.Errorf("importing %s: %w", from, err)
+			}
+
+			tm, err := NewTaskManager(filePath)
+			if err != nil {
+				return err
+			}
+
+			imported, updated := updateItems(tm.Items, imported, format)
+
+			count := 0
+			for _, item := range imported {
+				if item.Type == TypeTask {
+					count++
+				}
+			}
+
+			tm.Items = mergeItems(tm.Items, imported)
+
+			if err := tm.Save(); err != nil {
+				return fmt.Errorf("saving file: %w", err)
+			}
+
+			if porcelain != "" {
+				printPorcelain("imported", count, updated)
+				return nil
+			}
+
+			if updated > 0 {
+				fmt.Printf("Imported %d task(s), updated %d\n", count, updated)
+			} else {
+				fmt.Printf("Imported %d task(s)\n", count)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Format to import from")
+	cmd.Flags().StringSliceVar(&mapping, "map", nil, "Map task fields to CSV columns (title=Summary,section=Epic,done=Status==Closed)")
+	cmd.MarkFlagRequired("from")
+	cmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return formatNames(true), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newExportCommand() *cobra.Command {
+	var (
+		to     string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export tasks to another format",
+		Long: `Export the tasks of the markdown file to another task list format.
+The result is written to standard output unless --output is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := lookupFormat(to, false)
+			if err != nil {
+				return err
+			}
+
+			items, err := parseMarkdownFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			if output == "" || output == "-" {
+				return format.Export(os.Stdout, items)
+			}
+
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create output: %w", err)
+			}
+			if err := format.Export(file, items); err != nil {
+				file.Close()
+				return err
+			}
+			return file.Close()
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Format to export to")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to this file instead of standard output")
+	cmd.MarkFlagRequired("to")
+	cmd.RegisterFlagCompletionFunc("to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return formatNames(false), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
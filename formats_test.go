@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupFormat(t *testing.T) {
+	_, err := lookupFormat("todotxt", true)
+	require.NoError(t, err)
+
+	_, err = lookupFormat("todotxt", false)
+	require.NoError(t, err)
+
+	_, err = lookupFormat("csv", true)
+	require.NoError(t, err)
+
+	_, err = lookupFormat("csv", false)
+	require.ErrorContains(t, err, "unsupported export format 'csv'")
+
+	_, err = lookupFormat("unknown", true)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported import format 'unknown'")
+}
+
+func TestMergeItems(t *testing.T) {
+	existing := []Item{
+		{Type: TypeTask, Content: "Top"},
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		{Type: TypeTask, Content: "Existing work"},
+		{Type: TypeSection, Level: 2, Content: "Meetings"},
+		{Type: TypeTask, Content: "Standup"},
+		{Type: TypeSection, Level: 1, Content: "Home"},
+		{Type: TypeTask, Content: "Laundry"},
+	}
+	imported := []Item{
+		{Type: TypeTask, Content: "New top"},
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		{Type: TypeTask, Content: "New work"},
+		{Type: TypeSection, Level: 2, Content: "Reviews"},
+		{Type: TypeTask, Content: "Review PR"},
+		{Type: TypeSection, Level: 1, Content: "Garden"},
+		{Type: TypeTask, Content: "Plant tulips"},
+	}
+
+	merged := mergeItems(existing, imported)
+	require.Equal(t, []string{
+		"Top", "New top",
+		"Work", "Existing work", "New work",
+		"Meetings", "Standup",
+		"Reviews", "Review PR",
+		"Home", "Laundry",
+		"Garden", "Plant tulips",
+	}, contents(merged))
+	require.Len(t, existing, 7, "Existing items are left untouched")
+}
+
+func TestMergeItems_Empty(t *testing.T) {
+	imported := []Item{
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		{Type: TypeTask, Content: "Task"},
+	}
+	require.Equal(t, []string{"Work", "Task"}, contents(mergeItems(nil, imported)))
+	require.Empty(t, mergeItems(nil, nil))
+}
+
+func TestBuildSections(t *testing.T) {
+	tasks := []Item{
+		{Type: TypeTask, Content: "Review PR"},
+		{Type: TypeTask, Content: "Call mom"},
+		{Type: TypeTask, Content: "Standup"},
+		{Type: TypeTask, Content: "Plan sprint"},
+		{Type: TypeTask, Content: "Deep"},
+	}
+	paths := [][]string{
+		{"Work", "Reviews"},
+		nil,
+		{"Work"},
+		{"Work", "Reviews"},
+		{"A", "B", "C", "D", "E", "F", "G"},
+	}
+
+	items := buildSections(tasks, paths)
+	require.Equal(t, []string{
+		"Call mom",
+		"Work", "Standup",
+		"Reviews", "Review PR", "Plan sprint",
+		"A", "B", "C", "D", "E", "F / G", "Deep",
+	}, contents(items))
+	require.Equal(t, 1, items[1].Level)
+	require.Equal(t, 2, items[3].Level)
+	require.Equal(t, 6, items[11].Level, "Deeper sections are flattened into the last heading level")
+}
+
+func TestUniqueKeys(t *testing.T) {
+	items := []Item{
+		{Type: TypeSection, Level: 1, Content: "Home"},
+		{Type: TypeTask, Content: "Water plants"},
+		{Type: TypeTask, Content: "Water plants"},
+		{Type: TypeTask, Content: "Call mom"},
+		{Type: TypeTask, Content: "Water plants"},
+	}
+	byContent := func(item Item, path []string) string { return item.Content }
+	suffix := func(key string, n int) string { return fmt.Sprintf("%s #%d", key, n) }
+
+	require.Equal(t, []string{"", "Water plants", "Water plants #2", "Call mom", "Water plants #3"}, uniqueKeys(items, byContent, suffix))
+	require.Equal(t, []string{"", "Water plants", "Water plants", "Call mom", "Water plants"}, uniqueKeys(items, byContent, nil))
+}
+
+func TestUpdateItems(t *testing.T) {
+	byContent := taskFormat{
+		Key: func(item Item, path []string) string { return item.Content },
+		Update: func(existing *Item, imported Item) {
+			*existing.Checked = *imported.Checked
+		},
+	}
+	task := func(content string, checked bool) Item {
+		return Item{Type: TypeTask, Content: content, Checked: &checked}
+	}
+
+	existing := []Item{
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		task("Deploy", false),
+	}
+	imported := []Item{
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		task("Deploy", true),
+		{Type: TypeSection, Level: 2, Content: "Empty after update"},
+		task("Deploy", true),
+		{Type: TypeSection, Level: 1, Content: "Home"},
+		task("Laundry", false),
+	}
+
+	remaining, updated := updateItems(existing, imported, byContent)
+	require.Equal(t, 2, updated)
+	require.Equal(t, []string{"Home", "Laundry"}, contents(remaining))
+	require.True(t, *existing[1].Checked)
+
+	remaining, updated = updateItems(existing, imported, taskFormats["todotxt"])
+	require.Equal(t, imported, remaining, "Formats without keys always add tasks")
+	require.Zero(t, updated)
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// gitHubClient reads and updates the issues of a repository with the GitHub REST API
+type gitHubClient struct {
+	API   string // Base URL of the API, such as https://api.github.com
+	Token string // Skipped when empty, only public repositories can be read then
+	HTTP  *http.Client
+}
+
+// gitHubIssue is an issue as returned by the API
+type gitHubIssue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`                  // "open" or "closed"
+	PullRequest *struct{} `json:"pull_request,omitempty"` // Set for pull requests, which are listed as issues too
+}
+
+// gitHubPageSize is the number of issues asked for per request, the maximum allowed
+const gitHubPageSize = 100
+
+// do sends a request to the API, encoding body and decoding the response into out when not nil
+func (c *gitHubClient) do(method, path string, body, out any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.API, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("github: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errorf(ExitNotFound, "github: %s not found (check the repository name and GITHUB_TOKEN)", path)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("github: %s %s: %s", method, path, resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errorf(ExitParse, "github: invalid response: %w", err)
+	}
+	return nil
+}
+
+// Issues returns the open and closed issues of the repository, oldest first, without pull requests
+func (c *gitHubClient) Issues(repo string) ([]gitHubIssue, error) {
+	var issues []gitHubIssue
+	for page := 1; ; page++ {
+		var batch []gitHubIssue
+		path := fmt.Sprintf("/repos/%s/issues?state=all&sort=created&direction=asc&per_page=%d&page=%d", repo, gitHubPageSize, page)
+		if err := c.do(http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+
+		for _, issue := range batch {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		if len(batch) < gitHubPageSize {
+			return issues, nil
+		}
+	}
+}
+
+// CloseIssue closes an issue of the repository
+func (c *gitHubClient) CloseIssue(repo string, number int) error {
+	return c.do(http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", repo, number), map[string]string{"state": "closed"}, nil)
+}
+
+// issueNumber returns the issue a task is linked to with issue: metadata (issue:42 or issue:#42)
+func issueNumber(item Item) (int, bool) {
+	value, ok := item.Metadata["issue"]
+	if !ok {
+		return 0, false
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+	return number, err == nil && number > 0
+}
+
+// gitHubState remembers the state of the issues at the end of the last sync,
+// so that a task checked since can be told apart from an issue reopened since
+type gitHubState struct {
+	Repo   string       `json:"repo"`   // Repository the file was synced with
+	Closed map[int]bool `json:"closed"` // Whether each issue linked to a task was closed, by number
+}
+
+// gitHubStatePath returns the path of the GitHub sync state of the markdown file
+func gitHubStatePath(filePath string) string {
+	return sidecarPath(filePath, "github.json")
+}
+
+// loadGitHubState reads the sync state of the markdown file, returning an empty state if there is none
+func loadGitHubState(filePath string) (gitHubState, error) {
+	var state gitHubState
+	err := readSyncState(gitHubStatePath(filePath), &state)
+	return state, err
+}
+
+// saveGitHubState writes the sync state of the markdown file
+func saveGitHubState(filePath string, state gitHubState) error {
+	return writeSyncState(gitHubStatePath(filePath), state)
+}
+
+// syncGitHub mirrors the issues of the repository in the tasks of the file:
+//   - open issues without a task are added, in section when not empty, with issue:<n> metadata;
+//   - tasks take the title of their issue, and are checked or unchecked to follow its state;
+//   - with closeIssues, tasks checked since the last sync close their issue instead of being unchecked,
+//     while issues reopened since the last sync reopen their task.
+//
+// Closed issues without a task and tasks whose issue no longer exists are left alone.
+// The state of the linked issues is recorded in state.
+func syncGitHub(tm *TaskManager, client *gitHubClient, state *gitHubState, repo, section string, closeIssues bool) ([]syncChange, error) {
+	issues, err := client.Issues(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make(map[int][]int)
+	for i, item := range tm.Items {
+		if number, ok := issueNumber(item); ok && item.Type == TypeTask {
+			linked[number] = append(linked[number], i)
+		}
+	}
+
+	dates := newDateContext(tm.Config)
+	synced := make(map[int]bool)
+	var changes []syncChange
+	var added []Item
+	for _, issue := range issues {
+		key := "#" + strconv.Itoa(issue.Number)
+		closed := issue.State == "closed"
+		title, titleMetadata := parseTitle(issue.Title)
+
+		indices, ok := linked[issue.Number]
+		if !ok {
+			if closed {
+				continue
+			}
+			metadata := maps.Clone(titleMetadata)
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			metadata["issue"] = strconv.Itoa(issue.Number)
+			if tm.Config.Timestamps {
+				stampCreated(metadata, dates)
+			}
+			synced[issue.Number] = false
+			added = append(added, Item{
+				Type:     TypeTask,
+				Content:  title,
+				Checked:  func() *bool { b := false; return &b }(),
+				Metadata: metadata,
+			})
+			changes = append(changes, syncChange{Action: "added", Key: key, Content: issue.Title})
+			continue
+		}
+
+		for _, index := range indices {
+			item := &tm.Items[index]
+			if item.Content != title {
+				item.Content = title
+				for name, value := range titleMetadata {
+					if _, ok := item.Metadata[name]; !ok {
+						item.Metadata[name] = value
+					}
+				}
+				changes = append(changes, syncChange{Action: "renamed", Key: key, Content: issue.Title})
+			}
+
+			checked := *item.Checked
+			switch {
+			case checked == closed:
+			case checked && closeIssues && !state.Closed[issue.Number]:
+				// Checked since the last sync, rather than reopened on GitHub
+				if err := client.CloseIssue(repo, issue.Number); err != nil {
+					return nil, err
+				}
+				closed = true
+				changes = append(changes, syncChange{Action: "closed", Key: key, Content: item.Content})
+			case closed:
+				if err := tm.ToggleTask(index, true); err != nil {
+					return nil, err
+				}
+				if tm.Config.Timestamps {
+					if err := tm.SetMetadata(index, map[string]string{"completed": dates.today()}, nil); err != nil {
+						return nil, err
+					}
+				}
+				changes = append(changes, syncChange{Action: "done", Key: key, Content: item.Content})
+			default:
+				if err := tm.ToggleTask(index, false); err != nil {
+					return nil, err
+				}
+				if err := tm.SetMetadata(index, nil, []string{"completed"}); err != nil {
+					return nil, err
+				}
+				changes = append(changes, syncChange{Action: "undone", Key: key, Content: item.Content})
+			}
+			synced[issue.Number] = closed
+		}
+	}
+
+	if len(added) > 0 {
+		paths := make([][]string, len(added))
+		if section != "" {
+			for i := range paths {
+				paths[i] = []string{section}
+			}
+		}
+		tm.Items = mergeItems(tm.Items, buildSections(added, paths))
+	}
+
+	state.Repo = repo
+	state.Closed = synced
+	return changes, nil
+}
+
+func newSyncGitHubCommand() *cobra.Command {
+	var (
+		repo        string
+		section     string
+		closeIssues bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "github",
+		Short: "Mirror the issues of a GitHub repository",
+		Long: `Mirror the issues of a GitHub repository in the markdown file.
+Open issues become tasks with issue:<n> metadata, added under --section when given; running it again
+updates their titles and checks or unchecks them as issues are closed or reopened, without duplicates.
+With --close-issues, checking a task closes its issue on the next sync instead of being undone,
+while reopening an issue still reopens its task. The state of the last sync is stored next to the
+markdown file (TODO.md uses TODO.github.json).
+The token is read from the GITHUB_TOKEN (or GH_TOKEN) environment variable, and the API URL from
+GITHUB_API_URL for GitHub Enterprise.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+				return errorf(ExitUsage, "invalid repository '%s' (expected owner/name)", repo)
+			}
+
+			state, err := loadGitHubState(filePath)
+			if err != nil {
+				return err
+			}
+			if state.Repo != repo {
+				state = gitHubState{}
+			}
+
+			tm, err := NewTaskManager(filePath)
+			if err != nil {
+				return err
+			}
+
+			client := &gitHubClient{
+				API:   cmp.Or(os.Getenv("GITHUB_API_URL"), "https://api.github.com"),
+				Token: cmp.Or(os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN")),
+				HTTP:  &http.Client{Timeout: 30 * time.Second},
+			}
+			changes, err := syncGitHub(tm, client, &state, repo, section, closeIssues)
+			if err != nil {
+				return err
+			}
+
+			if err := tm.Save(); err != nil {
+				return fmt.Errorf("saving file: %w", err)
+			}
+			if err := saveGitHubState(filePath, state); err != nil {
+				return err
+			}
+
+			return printSyncReport(repo, changes)
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "Repository as owner/name")
+	cmd.Flags().StringVar(&section, "section", "", "Section new issues are added to (default: top of the file)")
+	cmd.Flags().BoolVar(&closeIssues, "close-issues", false, "Close the issues of checked tasks instead of unchecking them")
+	cmd.MarkFlagRequired("repo")
+
+	return cmd
+}
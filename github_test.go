@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves the issues of a single repository, owner/repo
+type fakeGitHub struct {
+	mu     sync.Mutex
+	issues []gitHubIssue
+	closed []int // Issues closed through the API
+}
+
+func newFakeGitHub(t *testing.T, issues ...gitHubIssue) (*fakeGitHub, *gitHubClient) {
+	fake := &fakeGitHub{issues: issues}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, &gitHubClient{API: server.URL, Token: "secret", HTTP: server.Client()}
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/repos/owner/repo/issues")
+	switch {
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodGet && path == "":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		start := min((page-1)*perPage, len(f.issues))
+		json.NewEncoder(w).Encode(f.issues[start:min(start+perPage, len(f.issues))])
+	case r.Method == http.MethodPatch:
+		number, _ := strconv.Atoi(strings.TrimPrefix(path, "/"))
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		for i := range f.issues {
+			if f.issues[i].Number == number {
+				f.issues[i].State = body["state"]
+				f.closed = append(f.closed, number)
+				json.NewEncoder(w).Encode(f.issues[i])
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// syncGitHubFile runs syncGitHub on the markdown file and saves it
+func syncGitHubFile(t *testing.T, path string, client *gitHubClient, section string, closeIssues bool) []syncChange {
+	t.Helper()
+	state, err := loadGitHubState(path)
+	require.NoError(t, err)
+	tm, err := NewTaskManager(path)
+	require.NoError(t, err)
+	changes, err := syncGitHub(tm, client, &state, "owner/repo", section, closeIssues)
+	require.NoError(t, err)
+	require.NoError(t, tm.Save())
+	require.NoError(t, saveGitHubState(path, state))
+	return changes
+}
+
+func TestIssueNumber(t *testing.T) {
+	tests := []struct {
+		value  string
+		number int
+		ok     bool
+	}{
+		{"42", 42, true},
+		{"#42", 42, true},
+		{"0", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			number, ok := issueNumber(Item{Type: TypeTask, Metadata: map[string]string{"issue": tt.value}})
+			require.Equal(t, tt.ok, ok)
+			if ok {
+				require.Equal(t, tt.number, number)
+			}
+		})
+	}
+
+	_, ok := issueNumber(Item{Type: TypeTask})
+	require.False(t, ok)
+}
+
+func TestSyncGitHub(t *testing.T) {
+	fake, client := newFakeGitHub(t,
+		gitHubIssue{Number: 1, Title: "Crash on start", State: "open"},
+		gitHubIssue{Number: 2, Title: "Old bug", State: "closed"},
+		gitHubIssue{Number: 3, Title: "Add dark mode", State: "open", PullRequest: &struct{}{}},
+		gitHubIssue{Number: 4, Title: "Typo in README", State: "open"},
+	)
+	path := createTestFile(t, `- [ ] Write release notes
+
+# Backlog
+- [ ] Plan v2
+`)
+
+	changes := syncGitHubFile(t, path, client, "Backlog", false)
+	require.Equal(t, []string{"added Crash on start", "added Typo in README"}, syncActions(changes))
+	require.Equal(t, `- [ ] Write release notes
+
+# Backlog
+
+- [ ] Plan v2
+- [ ] Crash on start issue:1
+- [ ] Typo in README issue:4
+`, readTestFile(t, path))
+
+	// Running it again adds no duplicates
+	require.Empty(t, syncGitHubFile(t, path, client, "Backlog", false))
+
+	// Issues closed, reopened and renamed on GitHub
+	fake.issues[0].State = "closed"
+	fake.issues[3].Title = "Typo in the README"
+	changes = syncGitHubFile(t, path, client, "Backlog", false)
+	require.Equal(t, []string{"done Crash on start", "renamed Typo in the README"}, syncActions(changes))
+	require.Contains(t, readTestFile(t, path), "- [x] Crash on start issue:1\n- [ ] Typo in the README issue:4\n")
+
+	fake.issues[0].State = "open"
+	changes = syncGitHubFile(t, path, client, "Backlog", false)
+	require.Equal(t, []string{"undone Crash on start"}, syncActions(changes))
+
+	// Checked tasks are unchecked while their issue is open, unless issues are closed
+	tm, err := NewTaskManager(path)
+	require.NoError(t, err)
+	require.NoError(t, tm.ToggleTask(4, true))
+	require.NoError(t, tm.Save())
+
+	changes = syncGitHubFile(t, path, client, "Backlog", true)
+	require.Equal(t, []string{"closed Typo in the README"}, syncActions(changes))
+	require.Equal(t, []int{4}, fake.closed)
+	require.Equal(t, "closed", fake.issues[3].State)
+	require.Contains(t, readTestFile(t, path), "- [x] Typo in the README issue:4\n")
+	require.Empty(t, syncGitHubFile(t, path, client, "Backlog", true))
+
+	require.NoError(t, tm.ToggleTask(3, true))
+	require.NoError(t, tm.Save())
+	changes = syncGitHubFile(t, path, client, "Backlog", false)
+	require.Equal(t, []string{"undone Crash on start"}, syncActions(changes))
+}
+
+func TestSyncGitHub_MetadataInTitle(t *testing.T) {
+	_, client := newFakeGitHub(t, gitHubIssue{Number: 1, Title: "Support key:value syntax", State: "open"})
+	path := createTestFile(t, "")
+
+	changes := syncGitHubFile(t, path, client, "", false)
+	require.Equal(t, []string{"added Support key:value syntax"}, syncActions(changes))
+	require.Equal(t, "- [ ] Support syntax issue:1 key:value\n", readTestFile(t, path))
+
+	// The title reads the same once saved, so it isn't renamed again
+	require.Empty(t, syncGitHubFile(t, path, client, "", false))
+	require.Empty(t, syncGitHubFile(t, path, client, "", false))
+	require.Equal(t, "- [ ] Support syntax issue:1 key:value\n", readTestFile(t, path))
+}
+
+func TestSyncGitHub_CloseIssues(t *testing.T) {
+	fake, client := newFakeGitHub(t, gitHubIssue{Number: 1, Title: "Crash on start", State: "open"})
+	path := createTestFile(t, "")
+	syncGitHubFile(t, path, client, "", true)
+
+	// Checking the task closes its issue
+	tm, err := NewTaskManager(path)
+	require.NoError(t, err)
+	require.NoError(t, tm.ToggleTask(0, true))
+	require.NoError(t, tm.Save())
+	require.Equal(t, []string{"closed Crash on start"}, syncActions(syncGitHubFile(t, path, client, "", true)))
+	require.Equal(t, "closed", fake.issues[0].State)
+
+	// Reopening the issue reopens the task instead of closing the issue again
+	fake.issues[0].State = "open"
+	require.Equal(t, []string{"undone Crash on start"}, syncActions(syncGitHubFile(t, path, client, "", true)))
+	require.Equal(t, []int{1}, fake.closed)
+	require.Equal(t, "- [ ] Crash on start issue:1\n", readTestFile(t, path))
+	require.Empty(t, syncGitHubFile(t, path, client, "", true))
+}
+
+func TestGitHubState(t *testing.T) {
+	path := createTestFile(t, "")
+	state, err := loadGitHubState(path)
+	require.NoError(t, err)
+	require.Equal(t, gitHubState{}, state)
+
+	state = gitHubState{Repo: "owner/repo", Closed: map[int]bool{1: true, 4: false}}
+	require.NoError(t, saveGitHubState(path, state))
+
+	loaded, err := loadGitHubState(path)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+}
+
+func TestSyncGitHub_Timestamps(t *testing.T) {
+	useClock(t, wednesday)
+	fake, client := newFakeGitHub(t, gitHubIssue{Number: 7, Title: "Flaky test", State: "open"})
+	path := createTestFile(t, "")
+	require.NoError(t, saveFileConfig(path, FileConfig{Timestamps: true}))
+
+	syncGitHubFile(t, path, client, "", false)
+	require.Equal(t, "- [ ] Flaky test created:2025-08-13 issue:7\n", readTestFile(t, path))
+
+	fake.issues[0].State = "closed"
+	syncGitHubFile(t, path, client, "", false)
+	require.Equal(t, "- [x] Flaky test completed:2025-08-13 created:2025-08-13 issue:7\n", readTestFile(t, path))
+
+	fake.issues[0].State = "open"
+	syncGitHubFile(t, path, client, "", false)
+	require.Equal(t, "- [ ] Flaky test created:2025-08-13 issue:7\n", readTestFile(t, path))
+}
+
+func TestGitHubClient_Issues(t *testing.T) {
+	var issues []gitHubIssue
+	for i := range gitHubPageSize + 5 {
+		issues = append(issues, gitHubIssue{Number: i + 1, Title: fmt.Sprintf("Issue %d", i+1), State: "open"})
+	}
+	_, client := newFakeGitHub(t, issues...)
+
+	listed, err := client.Issues("owner/repo")
+	require.NoError(t, err)
+	require.Equal(t, issues, listed)
+
+	_, err = client.Issues("owner/other")
+	require.Equal(t, ExitNotFound, exitCode(err))
+
+	client.Token = ""
+	_, err = client.Issues("owner/repo")
+	require.ErrorContains(t, err, "401 Unauthorized")
+}
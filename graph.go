@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// graphWriter writes the parts of a task graph in a specific syntax
+type graphWriter interface {
+	begin()
+	beginSection(index int, item Item, depth int)
+	endSection(depth int)
+	task(index int, item Item, depth int)
+	edge(from, to int)
+	end(done []int)
+}
+
+// writeGraph walks the items, nesting tasks in their sections, followed by the dependency edges
+func writeGraph(gw graphWriter, items []Item) {
+	gw.begin()
+
+	var open []Item // Sections currently open
+	var done []int
+
+	for i, item := range items {
+		switch item.Type {
+		case TypeSection:
+			for len(open) > 0 && open[len(open)-1].Level >= item.Level {
+				open = open[:len(open)-1]
+				gw.endSection(len(open))
+			}
+			gw.beginSection(i, item, len(open))
+			open = append(open, item)
+
+		case TypeTask:
+			gw.task(i, item, len(open))
+			if item.Checked != nil && *item.Checked {
+				done = append(done, i)
+			}
+		}
+	}
+
+	for len(open) > 0 {
+		open = open[:len(open)-1]
+		gw.endSection(len(open))
+	}
+
+	graph := buildDependencyGraph(items)
+	for _, task := range slices.Sorted(maps.Keys(graph.Prerequisites)) {
+		for _, prerequisite := range graph.Prerequisites[task] {
+			gw.edge(prerequisite, task)
+		}
+	}
+
+	gw.end(done)
+}
+
+// indent returns the indentation for the nesting depth
+func indent(depth int) string {
+	return strings.Repeat("  ", depth+1)
+}
+
+// dotWriter writes graphs in the Graphviz DOT language
+type dotWriter struct {
+	w io.Writer
+}
+
+// dotQuote quotes a string for DOT
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (d dotWriter) begin() {
+	fmt.Fprintln(d.w, "digraph tasks {")
+	fmt.Fprintln(d.w, "  rankdir=LR;")
+	fmt.Fprintln(d.w, "  node [shape=box];")
+}
+
+func (d dotWriter) beginSection(index int, item Item, depth int) {
+	fmt.Fprintf(d.w, "%ssubgraph cluster_s%d {\n", indent(depth), index+1)
+	fmt.Fprintf(d.w, "%slabel=%s;\n", indent(depth+1), dotQuote(item.Content))
+}
+
+func (d dotWriter) endSection(depth int) {
+	fmt.Fprintf(d.w, "%s}\n", indent(depth))
+}
+
+func (d dotWriter) task(index int, item Item, depth int) {
+	style := ""
+	if item.Checked != nil && *item.Checked {
+		style = ", style=\"filled,dashed\", fillcolor=\"#eeeeee\", fontcolor=\"#888888\""
+	}
+	fmt.Fprintf(d.w, "%st%d [label=%s%s];\n", indent(depth), index+1, dotQuote(item.Content), style)
+}
+
+func (d dotWriter) edge(from, to int) {
+	fmt.Fprintf(d.w, "  t%d -> t%d;\n", from+1, to+1)
+}
+
+func (d dotWriter) end(done []int) {
+	fmt.Fprintln(d.w, "}")
+}
+
+// mermaidWriter writes graphs as Mermaid flowcharts
+type mermaidWriter struct {
+	w io.Writer
+}
+
+// mermaidQuote quotes a string for a Mermaid label
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+func (m mermaidWriter) begin() {
+	fmt.Fprintln(m.w, "flowchart LR")
+}
+
+func (m mermaidWriter) beginSection(index int, item Item, depth int) {
+	fmt.Fprintf(m.w, "%ssubgraph s%d[%s]\n", indent(depth), index+1, mermaidQuote(item.Content))
+}
+
+func (m mermaidWriter) endSection(depth int) {
+	fmt.Fprintf(m.w, "%send\n", indent(depth))
+}
+
+func (m mermaidWriter) task(index int, item Item, depth int) {
+	fmt.Fprintf(m.w, "%st%d[%s]\n", indent(depth), index+1, mermaidQuote(item.Content))
+}
+
+func (m mermaidWriter) edge(from, to int) {
+	fmt.Fprintf(m.w, "  t%d --> t%d\n", from+1, to+1)
+}
+
+func (m mermaidWriter) end(done []int) {
+	if len(done) == 0 {
+		return
+	}
+
+	nodes := make([]string, len(done))
+	for i, index := range done {
+		nodes[i] = fmt.Sprintf("t%d", index+1)
+	}
+	fmt.Fprintln(m.w, "  classDef done fill:#eeeeee,color:#888888,stroke-dasharray:5 5")
+	fmt.Fprintf(m.w, "  class %s done\n", strings.Join(nodes, ","))
+}
+
+// graphFormats lists the supported graph formats
+var graphFormats = map[string]func(w io.Writer) graphWriter{
+	"dot":     func(w io.Writer) graphWriter { return dotWriter{w} },
+	"mermaid": func(w io.Writer) graphWriter { return mermaidWriter{w} },
+}
+
+func newGraphCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export tasks and dependencies as a graph",
+		Long: `Export the sections, tasks and dependencies (after:/blocks: metadata) as a graph.
+Sections become nested clusters, dependency edges point from a prerequisite to the task waiting on it
+and completed tasks are greyed out. Supported formats are dot (Graphviz) and mermaid.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newWriter, ok := graphFormats[format]
+			if !ok {
+				return errorf(ExitUsage, "unsupported graph format '%s' (must be dot or mermaid)", format)
+			}
+
+			items, err := parseMarkdownFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			writeGraph(newWriter(os.Stdout), items)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "dot", "Graph format (dot, mermaid)")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return slices.Sorted(maps.Keys(graphFormats)), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
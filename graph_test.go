@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const graphContent = `- [ ] Loose "task"
+# Release
+- [x] Build id:build
+- [ ] Deploy after:build
+## Docs
+- [ ] Notes
+# Other
+- [ ] Later
+`
+
+func TestWriteGraph_DOT(t *testing.T) {
+	filename := createTestFile(t, graphContent)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writeGraph(dotWriter{&buf}, items)
+
+	expected := `digraph tasks {
+  rankdir=LR;
+  node [shape=box];
+  t1 [label="Loose \"task\""];
+  subgraph cluster_s2 {
+    label="Release";
+    t3 [label="Build", style="filled,dashed", fillcolor="#eeeeee", fontcolor="#888888"];
+    t4 [label="Deploy"];
+    subgraph cluster_s5 {
+      label="Docs";
+      t6 [label="Notes"];
+    }
+  }
+  subgraph cluster_s7 {
+    label="Other";
+    t8 [label="Later"];
+  }
+  t3 -> t4;
+}
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteGraph_Mermaid(t *testing.T) {
+	filename := createTestFile(t, graphContent)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writeGraph(mermaidWriter{&buf}, items)
+
+	expected := `flowchart LR
+  t1["Loose #quot;task#quot;"]
+  subgraph s2["Release"]
+    t3["Build"]
+    t4["Deploy"]
+    subgraph s5["Docs"]
+      t6["Notes"]
+    end
+  end
+  subgraph s7["Other"]
+    t8["Later"]
+  end
+  t3 --> t4
+  classDef done fill:#eeeeee,color:#888888,stroke-dasharray:5 5
+  class t3 done
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteGraph_Mermaid_NothingDone(t *testing.T) {
+	items := []Item{{Type: TypeTask, Content: "Open", Checked: func() *bool { b := false; return &b }()}}
+
+	var buf bytes.Buffer
+	writeGraph(mermaidWriter{&buf}, items)
+	require.NotContains(t, buf.String(), "classDef")
+}
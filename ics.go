@@ -0,0 +1,433 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// iCalendar (RFC 5545) files store tasks as VTODO components:
+//
+//	BEGIN:VTODO
+//	UID:build@tasks
+//	SUMMARY:Write the build script #ci
+//	STATUS:NEEDS-ACTION
+//	DUE;VALUE=DATE:20250815
+//	PRIORITY:3
+//	CATEGORIES:Work,Backend,#ci
+//	END:VTODO
+//
+// CATEGORIES holds the section path followed by the tags of the task.
+// Imported tasks keep their UID as uid: metadata, so importing the file again updates them.
+
+// icsDateLayout is the layout of iCalendar DATE values
+const icsDateLayout = "20060102"
+
+// icsTextEscaper escapes iCalendar TEXT values
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "")
+
+// icsPriorities maps priority ranks, from highest to lowest, to iCalendar priorities (1 is the highest, 9 the lowest)
+var icsPriorities = []int{1, 3, 5, 7, 9}
+
+// icsUID returns the stable UID of a task: its uid: metadata, kept from an import,
+// its id: metadata, or else a hash of its section path and description
+func icsUID(item Item, path []string) string {
+	if uid, ok := item.Metadata["uid"]; ok {
+		return uid
+	}
+	if id, ok := item.Metadata["id"]; ok {
+		return id + "@tasks"
+	}
+	sum := sha1.Sum([]byte(strings.Join(slices.Concat(path, []string{item.Content}), "\x00")))
+	return hex.EncodeToString(sum[:8]) + "@tasks"
+}
+
+// icsUIDSuffix returns the UID of the nth task having the given UID, such as build-2@tasks
+func icsUIDSuffix(uid string, n int) string {
+	return fmt.Sprintf("%s-%d@tasks", strings.TrimSuffix(uid, "@tasks"), n)
+}
+
+// icsPriority converts a priority to an iCalendar priority, if possible
+func icsPriority(value string) (int, bool) {
+	rank, ok := priorityRank(value)
+	if !ok || rank < 0 {
+		return 0, false
+	}
+	return icsPriorities[min(rank, len(icsPriorities)-1)], true
+}
+
+// writeICSLine writes a content line, folded at 75 octets as required by RFC 5545
+func writeICSLine(w io.Writer, line string) error {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := utf8.RuneLen(r)
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	b.WriteString("\r\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatVTODO returns the content lines of a task as a VTODO component
+func formatVTODO(item Item, path []string, stamp time.Time) []string {
+	dates := newDateContext(FileConfig{})
+
+	lines := []string{
+		"BEGIN:VTODO",
+		"UID:" + icsTextEscaper.Replace(icsUID(item, path)),
+		"DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"),
+		"SUMMARY:" + icsTextEscaper.Replace(item.Content),
+	}
+
+	if item.Checked != nil && *item.Checked {
+		lines = append(lines, "STATUS:COMPLETED")
+		if completed, ok := dates.Parse(item.Metadata["completed"]); ok {
+			lines = append(lines, "COMPLETED:"+completed.Format(icsDateLayout)+"T000000Z")
+		}
+	} else {
+		lines = append(lines, "STATUS:NEEDS-ACTION")
+	}
+
+	if due, ok := dates.Parse(item.Metadata["due"]); ok {
+		lines = append(lines, "DUE;VALUE=DATE:"+due.Format(icsDateLayout))
+	}
+
+	if value, ok := metadataValue(item, "priority"); ok {
+		if priority, ok := icsPriority(value); ok {
+			lines = append(lines, fmt.Sprintf("PRIORITY:%d", priority))
+		}
+	}
+
+	var categories []string
+	for _, category := range slices.Concat(path, item.Tags) {
+		categories = append(categories, icsTextEscaper.Replace(category))
+	}
+	if len(categories) > 0 {
+		lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+
+	return append(lines, "END:VTODO")
+}
+
+// exportICS writes every task as a VTODO of an iCalendar file
+func exportICS(w io.Writer, items []Item) error {
+	stamp := clock.Now()
+	paths := sectionPaths(items)
+	uids := uniqueKeys(items, icsUID, icsUIDSuffix)
+
+	var todos [][]string
+	for i, item := range items {
+		if item.Type == TypeTask {
+			if uids[i] != icsUID(item, paths[i]) {
+				// Identical tasks are exported with the distinct UIDs they are given
+				item.Metadata = maps.Clone(item.Metadata)
+				if item.Metadata == nil {
+					item.Metadata = make(map[string]string)
+				}
+				item.Metadata["uid"] = uids[i]
+			}
+			todos = append(todos, formatVTODO(item, paths[i], stamp))
+		}
+	}
+	return writeVCALENDAR(w, todos)
+}
+
+// writeVCALENDAR writes VTODO components wrapped in a VCALENDAR object
+func writeVCALENDAR(w io.Writer, todos [][]string) error {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//tasks//tasks//EN"}
+	for _, todo := range todos {
+		lines = append(lines, todo...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if err := writeICSLine(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// icsProperty is a content line of an iCalendar file, such as DUE;VALUE=DATE:20250815
+type icsProperty struct {
+	Name   string
+	Params string // Raw parameters, without the leading semicolon
+	Value  string
+}
+
+// parseICSLine splits an unfolded content line into a property
+func parseICSLine(line string) (icsProperty, bool) {
+	// The value starts at the first colon outside quoted parameter values
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			name, params, _ := strings.Cut(line[:i], ";")
+			return icsProperty{Name: strings.ToUpper(name), Params: params, Value: line[i+1:]}, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+// readICSLines reads the content lines of an iCalendar file, unfolding continuation lines
+func readICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading iCalendar file: %w", err)
+	}
+	return lines, nil
+}
+
+// unescapeICSText unescapes an iCalendar TEXT value
+func unescapeICSText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitICSList splits a list of TEXT values on unescaped commas
+func splitICSList(s string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescapeICSText(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescapeICSText(s[start:]))
+}
+
+// parseICSDateTime parses an iCalendar DATE-TIME value, in UTC when it ends with Z and in local time otherwise
+func parseICSDateTime(value string) (time.Time, bool) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, time.Local); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// parseICSDate returns the ISO date of an iCalendar DATE or DATE-TIME value
+func parseICSDate(value string) (string, bool) {
+	if len(value) < len(icsDateLayout) {
+		return "", false
+	}
+	date, err := time.Parse(icsDateLayout, value[:len(icsDateLayout)])
+	if err != nil {
+		return "", false
+	}
+	return date.Format(time.DateOnly), true
+}
+
+// taskPriorityFromICS converts an iCalendar priority to a priority value, 0 meaning undefined
+func taskPriorityFromICS(value string) (string, bool) {
+	switch strings.TrimSpace(value) {
+	case "1":
+		return "highest", true
+	case "2", "3", "4":
+		return "high", true
+	case "5":
+		return "medium", true
+	case "6", "7", "8":
+		return "low", true
+	case "9":
+		return "lowest", true
+	}
+	return "", false
+}
+
+// icsTodo is a task read from a VTODO component
+type icsTodo struct {
+	Task     Item
+	Path     []string  // Section path given by the categories
+	Modified time.Time // LAST-MODIFIED, zero if unknown
+}
+
+// parseVTODO converts the properties of a VTODO to a task.
+// Categories starting with # or @ are tags, added to the description if missing; the others give the section path.
+func parseVTODO(properties []icsProperty) icsTodo {
+	var modified time.Time
+	checked := false
+	metadata := make(map[string]string)
+	var summary string
+	var path, tags []string
+
+	for _, property := range properties {
+		switch property.Name {
+		case "UID":
+			metadata["uid"] = unescapeICSText(property.Value)
+		case "SUMMARY":
+			summary = strings.Join(strings.Fields(unescapeICSText(property.Value)), " ")
+		case "STATUS":
+			status := strings.ToUpper(property.Value)
+			checked = status == "COMPLETED" || status == "CANCELLED"
+		case "COMPLETED":
+			checked = true
+			if date, ok := parseICSDate(property.Value); ok {
+				metadata["completed"] = date
+			}
+		case "DUE":
+			if date, ok := parseICSDate(property.Value); ok {
+				metadata["due"] = date
+			}
+		case "LAST-MODIFIED":
+			modified, _ = parseICSDateTime(property.Value)
+		case "PRIORITY":
+			if priority, ok := taskPriorityFromICS(property.Value); ok {
+				metadata["priority"] = priority
+			}
+		case "CATEGORIES":
+			for _, category := range splitICSList(property.Value) {
+				category = strings.TrimSpace(category)
+				switch {
+				case category == "":
+				case strings.HasPrefix(category, "#") || strings.HasPrefix(category, "@"):
+					tags = append(tags, category)
+				default:
+					path = append(path, category)
+				}
+			}
+		}
+	}
+
+	description, summaryMetadata := parseTitle(summary)
+	for key, value := range summaryMetadata {
+		if _, ok := metadata[key]; !ok {
+			metadata[key] = value
+		}
+	}
+
+	existing := parseTags(description)
+	for _, tag := range tags {
+		if !slices.Contains(existing, strings.ToLower(tag)) {
+			description += " " + tag
+		}
+	}
+
+	task := Item{
+		Type:     TypeTask,
+		Content:  description,
+		Checked:  &checked,
+		Metadata: metadata,
+		Tags:     parseTags(description),
+	}
+	return icsTodo{Task: task, Path: path, Modified: modified}
+}
+
+// readVTODOs reads the VTODO components of an iCalendar file.
+// Components without a summary or repeating a UID, such as recurrence overrides, are skipped.
+func readVTODOs(r io.Reader) ([]icsTodo, error) {
+	lines, err := readICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []icsTodo
+	seen := make(map[string]bool)
+
+	var properties []icsProperty
+	inTodo := false
+	depth := 0 // Nesting of components inside the VTODO, such as VALARM
+	for _, line := range lines {
+		property, ok := parseICSLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case property.Name == "BEGIN" && strings.EqualFold(property.Value, "VTODO") && !inTodo:
+			inTodo = true
+			properties = nil
+		case !inTodo:
+		case property.Name == "BEGIN":
+			depth++
+		case property.Name == "END" && depth > 0:
+			depth--
+		case property.Name == "END":
+			inTodo = false
+			todo := parseVTODO(properties)
+			uid := todo.Task.Metadata["uid"]
+			if todo.Task.Content == "" || (uid != "" && seen[uid]) {
+				continue
+			}
+			seen[uid] = true
+			todos = append(todos, todo)
+		case depth == 0:
+			properties = append(properties, property)
+		}
+	}
+
+	return todos, nil
+}
+
+// importICS reads the VTODO components of an iCalendar file, placing tasks under the sections given by their categories
+func importICS(r io.Reader) ([]Item, error) {
+	todos, err := readVTODOs(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]Item, len(todos))
+	paths := make([][]string, len(todos))
+	for i, todo := range todos {
+		tasks[i], paths[i] = todo.Task, todo.Path
+	}
+	return buildSections(tasks, paths), nil
+}
+
+// updateICSTask updates a task imported before with the status and dates of its VTODO
+func updateICSTask(existing *Item, imported Item) {
+	*existing.Checked = *imported.Checked
+	if existing.Metadata == nil {
+		existing.Metadata = make(map[string]string)
+	}
+	for _, key := range []string{"due", "completed"} {
+		if value, ok := imported.Metadata[key]; ok {
+			existing.Metadata[key] = value
+		} else {
+			delete(existing.Metadata, key)
+		}
+	}
+}
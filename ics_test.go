@@ -0,0 +1,209 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestICSUID(t *testing.T) {
+	task := Item{Type: TypeTask, Content: "Write docs"}
+	uid := icsUID(task, []string{"Work"})
+	require.Equal(t, uid, icsUID(task, []string{"Work"}), "UIDs are stable")
+	require.NotEqual(t, uid, icsUID(task, []string{"Home"}))
+	require.True(t, strings.HasSuffix(uid, "@tasks"))
+
+	task.Metadata = map[string]string{"id": "docs"}
+	require.Equal(t, "docs@tasks", icsUID(task, []string{"Work"}))
+
+	task.Metadata["uid"] = "abc-123@example.com"
+	require.Equal(t, "abc-123@example.com", icsUID(task, []string{"Work"}))
+}
+
+func TestICSUIDSuffix(t *testing.T) {
+	require.Equal(t, "build-2@tasks", icsUIDSuffix("build@tasks", 2))
+	require.Equal(t, "abc@example.com-3@tasks", icsUIDSuffix("abc@example.com", 3))
+}
+
+func TestICSPriority(t *testing.T) {
+	for value, want := range map[string]int{"highest": 1, "high": 3, "medium": 5, "low": 7, "lowest": 9, "A": 1, "E": 9, "Z": 9} {
+		priority, ok := icsPriority(value)
+		require.True(t, ok, value)
+		require.Equal(t, want, priority, value)
+	}
+
+	_, ok := icsPriority("soon")
+	require.False(t, ok)
+}
+
+func TestWriteICSLine(t *testing.T) {
+	var buf strings.Builder
+	require.NoError(t, writeICSLine(&buf, "SUMMARY:short"))
+	require.Equal(t, "SUMMARY:short\r\n", buf.String())
+
+	buf.Reset()
+	long := "SUMMARY:" + strings.Repeat("é", 40)
+	require.NoError(t, writeICSLine(&buf, long))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Len(t, lines, 2)
+	require.LessOrEqual(t, len(lines[0]), 75)
+	require.True(t, strings.HasPrefix(lines[1], " "))
+	require.Equal(t, long, lines[0]+lines[1][1:], "Folding doesn't split characters")
+}
+
+func TestExportICS(t *testing.T) {
+	useClock(t, time.Date(2025, 8, 13, 15, 4, 5, 0, time.UTC))
+
+	content := `- [ ] Call mom
+# Work
+## Backend
+- [ ] Fix build, then deploy #ci @alice due:2025-08-15 priority:high id:build
+- [x] Write tests completed:2025-08-12
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportICS(&buf, items))
+
+	output := buf.String()
+	require.True(t, strings.HasPrefix(output, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(output, "END:VTODO\r\nEND:VCALENDAR\r\n"))
+	require.Equal(t, 3, strings.Count(output, "BEGIN:VTODO"))
+	require.Contains(t, output, strings.Join([]string{
+		"BEGIN:VTODO",
+		"UID:build@tasks",
+		"DTSTAMP:20250813T150405Z",
+		`SUMMARY:Fix build\, then deploy #ci @alice`,
+		"STATUS:NEEDS-ACTION",
+		"DUE;VALUE=DATE:20250815",
+		"PRIORITY:3",
+		"CATEGORIES:Work,Backend,#ci,@alice",
+		"END:VTODO",
+	}, "\r\n"))
+	require.Contains(t, output, "SUMMARY:Write tests\r\nSTATUS:COMPLETED\r\nCOMPLETED:20250812T000000Z\r\nCATEGORIES:Work,Backend\r\n")
+	require.Contains(t, output, "SUMMARY:Call mom\r\nSTATUS:NEEDS-ACTION\r\nEND:VTODO")
+}
+
+func TestParseICSLine(t *testing.T) {
+	property, ok := parseICSLine(`DUE;VALUE=DATE:20250815`)
+	require.True(t, ok)
+	require.Equal(t, icsProperty{Name: "DUE", Params: "VALUE=DATE", Value: "20250815"}, property)
+
+	property, ok = parseICSLine(`ATTENDEE;CN="Doe: John":mailto:john@example.com`)
+	require.True(t, ok)
+	require.Equal(t, "mailto:john@example.com", property.Value, "Colons in quoted parameters don't end the name")
+
+	_, ok = parseICSLine("garbage")
+	require.False(t, ok)
+}
+
+func TestSplitICSList(t *testing.T) {
+	require.Equal(t, []string{"Work", "Back, end", `a\b`}, splitICSList(`Work,Back\, end,a\\b`))
+	require.Equal(t, "line one\nline two; done", unescapeICSText(`line one\nline two\; done`))
+}
+
+func TestImportICS(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTODO",
+		"UID:abc-1@example.com",
+		"SUMMARY:Renew passport before the summer holidays and check the visa requireme",
+		" nts",
+		"DUE:20251101T090000Z",
+		"PRIORITY:1",
+		"CATEGORIES:Home,Admin,@bob",
+		"BEGIN:VALARM",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:abc-2@example.com",
+		`SUMMARY:Buy milk\, eggs #shopping`,
+		"STATUS:COMPLETED",
+		"COMPLETED:20250810T120000Z",
+		"CATEGORIES:#shopping",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:abc-2@example.com",
+		"SUMMARY:Recurrence override",
+		"END:VTODO",
+		"BEGIN:VEVENT",
+		"SUMMARY:Not a task",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	items, err := importICS(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Buy milk, eggs #shopping",
+		"Home", "Admin",
+		"Renew passport before the summer holidays and check the visa requirements @bob",
+	}, contents(items))
+
+	require.Equal(t, TypeSection, items[1].Type)
+	require.Equal(t, 2, items[2].Level)
+
+	require.True(t, *items[0].Checked)
+	require.Equal(t, map[string]string{"uid": "abc-2@example.com", "completed": "2025-08-10"}, items[0].Metadata)
+
+	require.False(t, *items[3].Checked)
+	require.Equal(t, map[string]string{"uid": "abc-1@example.com", "due": "2025-11-01", "priority": "highest"}, items[3].Metadata)
+	require.Equal(t, []string{"@bob"}, items[3].Tags)
+}
+
+func TestICS_RoundTrip(t *testing.T) {
+	content := `# Work
+- [ ] Deploy due:2025-08-15 id:deploy
+- [ ] Write docs
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportICS(&buf, items))
+
+	// The calendar app completes a task and moves the other one
+	remote := strings.Replace(buf.String(), "STATUS:NEEDS-ACTION\r\nDUE;VALUE=DATE:20250815", "STATUS:COMPLETED\r\nCOMPLETED:20250814T100000Z", 1)
+	remote = strings.Replace(remote, "SUMMARY:Write docs\r\nSTATUS:NEEDS-ACTION", "SUMMARY:Write docs\r\nSTATUS:NEEDS-ACTION\r\nDUE;VALUE=DATE:20250820", 1)
+
+	imported, err := importICS(strings.NewReader(remote))
+	require.NoError(t, err)
+
+	remaining, updated := updateItems(items, imported, taskFormats["ics"])
+	require.Empty(t, remaining, "Known tasks are not added again")
+	require.Equal(t, 2, updated)
+
+	require.True(t, *items[1].Checked)
+	require.Equal(t, map[string]string{"id": "deploy", "completed": "2025-08-14"}, items[1].Metadata)
+	require.False(t, *items[2].Checked)
+	require.Equal(t, map[string]string{"due": "2025-08-20"}, items[2].Metadata)
+}
+
+func TestICS_RoundTrip_IdenticalTasks(t *testing.T) {
+	// Completing a recurring task leaves a record with the same description
+	content := `# Home
+- [x] Water plants due:2025-08-13
+- [ ] Water plants due:2025-08-20 recur:weekly
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportICS(&buf, items))
+
+	imported, err := importICS(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Home", "Water plants", "Water plants"}, contents(imported), "Identical tasks get distinct UIDs")
+	require.Equal(t, icsUIDSuffix(imported[1].Metadata["uid"], 2), imported[2].Metadata["uid"])
+
+	remaining, updated := updateItems(items, imported, taskFormats["ics"])
+	require.Empty(t, remaining)
+	require.Equal(t, 2, updated)
+	require.True(t, *items[1].Checked)
+	require.False(t, *items[2].Checked)
+}
@@ -21,6 +21,10 @@ var (
 
 // shouldUseColor checks if color output should be used
 func shouldUseColor() bool {
+	if porcelain != "" {
+		return false
+	}
+
 	switch colorMode {
 	case "always":
 		return true
@@ -48,16 +52,19 @@ type Item struct {
 	Children   []Item            // Child items (for hierarchical structure)
 	LineNumber int               // Line number in the original file (1-based)
 	Metadata   map[string]string // Task metadata (nil for sections)
+	BlockedBy  []string          // Stable IDs of open tasks this task waits on (computed on load, not saved)
+	Tags       []string          // Lowercased #tag and @person tokens of the description (tasks only)
+	EmojiKeys  []string          // Metadata keys written in Obsidian Tasks emoji syntax
 }
 
 // parseItemID parses a string ID and converts it to 0-based index
 func parseItemID(idStr string) (int, error) {
 	var id int
 	if _, err := fmt.Sscanf(idStr, "%d", &id); err != nil {
-		return -1, fmt.Errorf("invalid ID '%s'", idStr)
+		return -1, errorf(ExitParse, "invalid ID '%s'", idStr)
 	}
 	if id < 1 {
-		return -1, fmt.Errorf("ID must be greater than 0")
+		return -1, errorf(ExitParse, "ID must be greater than 0")
 	}
 	return id - 1, nil // Convert to 0-based
 }
@@ -116,7 +123,18 @@ func formatItem(item Item, index int) string {
 				checkBox = "[ ]"
 			}
 		}
-		taskStr := "- " + checkBox + " " + item.Content
+		content := item.Content
+		if shouldUseColor() {
+			base := ""
+			if rank, ok := taskPriority(item); ok && (item.Checked == nil || !*item.Checked) {
+				base = priorityColor(rank)
+			}
+			content = highlightTags(content, base)
+			if base != "" {
+				content = base + content + "\033[0m"
+			}
+		}
+		taskStr := "- " + checkBox + " " + content
 
 		// Add metadata if it exists
 		if len(item.Metadata) > 0 {
@@ -128,11 +146,24 @@ func formatItem(item Item, index int) string {
 			}
 			slices.Sort(keys)
 
+			// Highlight the due date of overdue and due-today tasks
+			dueColor := "\033[32m"
+			switch newDateContext(FileConfig{}).DueStatus(item) {
+			case DueOverdue:
+				dueColor = "\033[1;91m" // Bold bright red
+			case DueToday:
+				dueColor = "\033[1;93m" // Bold bright yellow
+			}
+
 			for _, k := range keys {
 				v := item.Metadata[k]
 				if shouldUseColor() {
 					// Green color for metadata
-					metadataParts = append(metadataParts, fmt.Sprintf("\033[32m%s:%s\033[0m", k, v))
+					color := "\033[32m"
+					if k == "due" {
+						color = dueColor
+					}
+					metadataParts = append(metadataParts, fmt.Sprintf("%s%s:%s\033[0m", color, k, v))
 				} else {
 					metadataParts = append(metadataParts, fmt.Sprintf("%s:%s", k, v))
 				}
@@ -140,6 +171,15 @@ func formatItem(item Item, index int) string {
 			taskStr += " " + strings.Join(metadataParts, " ")
 		}
 
+		// Mark tasks waiting on open tasks
+		if len(item.BlockedBy) > 0 && (item.Checked == nil || !*item.Checked) {
+			blocked := "(blocked by " + strings.Join(item.BlockedBy, ", ") + ")"
+			if shouldUseColor() {
+				blocked = "\033[2;31m" + blocked + "\033[0m" // Dim red
+			}
+			taskStr += " " + blocked
+		}
+
 		if shouldUseColor() {
 			result = fmt.Sprintf("\033[33m%s\033[0m %s", idStr, taskStr)
 		} else {
@@ -208,6 +248,7 @@ func parseMarkdownFile(filePath string) ([]Item, error) {
 					Checked:    &checked,
 					LineNumber: lineNumber,
 					Metadata:   nil,
+					Tags:       parseTags(content),
 				})
 			} else {
 				// Use parsed result
@@ -218,6 +259,8 @@ func parseMarkdownFile(filePath string) ([]Item, error) {
 					Checked:    &parsedTask.Completed,
 					LineNumber: lineNumber,
 					Metadata:   parsedTask.Metadata,
+					Tags:       parseTags(parsedTask.Description),
+					EmojiKeys:  parsedTask.EmojiKeys,
 				})
 			}
 			continue
@@ -228,6 +271,8 @@ func parseMarkdownFile(filePath string) ([]Item, error) {
 		return nil, fmt.Errorf("error reading file: %w", err)
 	}
 
+	resolveDependencies(items)
+
 	return items, nil
 }
 
@@ -259,6 +304,30 @@ func deleteItem(items []Item, index int) []Item {
 	}
 }
 
+// sectionPaths returns, for every item, the names of the sections it is nested in.
+// A section's path ends with its own name; tasks before the first section have an empty path.
+func sectionPaths(items []Item) [][]string {
+	paths := make([][]string, len(items))
+	var stack []Item
+
+	for i, item := range items {
+		if item.Type == TypeSection {
+			for len(stack) > 0 && stack[len(stack)-1].Level >= item.Level {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, item)
+		}
+
+		path := make([]string, 0, len(stack))
+		for _, section := range stack {
+			path = append(path, section.Content)
+		}
+		paths[i] = path
+	}
+
+	return paths
+}
+
 // fuzzyMatch performs case-insensitive fuzzy matching
 // Returns a score between 0 and 1, where 1 is a perfect match
 func fuzzyMatch(pattern, text string) float64 {
@@ -418,8 +487,8 @@ func getVersion() string {
 
 func main() {
 	if err := run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		printError(os.Stderr, err)
+		os.Exit(int(exitCode(err)))
 	}
 }
 
@@ -431,11 +500,34 @@ func run() error {
 It provides Unix-friendly commands for manipulating tasks and sections stored in markdown files,
 designed for scripting and integration with other tools like fzf and shell workflows.`,
 		Version: getVersion(),
+		// Errors are printed by main, and usage by run for command line errors only
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	// Errors returned before the command runs come from the command line
+	started := false
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkPorcelainVersion(); err != nil {
+			return err
+		}
+		// Cobra checks required flags after this hook, do it now so they count as command line errors
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return err
+		}
+		started = true
+		return nil
 	}
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&filePath, "file", "TODO.md", "Path to the markdown file")
 	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "When to use color output (always, never, auto)")
+	rootCmd.PersistentFlags().BoolVar(&showAll, "all", false, "Include snoozed tasks in listings and completions")
+	rootCmd.PersistentFlags().StringVar(&porcelain, "porcelain", "", "Print stable, color-free records for scripts (version: v1)")
+	rootCmd.PersistentFlags().Lookup("porcelain").NoOptDefVal = porcelainVersions[0]
 
 	// Add subcommands
 	rootCmd.AddCommand(
@@ -444,31 +536,163 @@ designed for scripting and integration with other tools like fzf and shell workf
 		newDoneCommand(),
 		newUndoCommand(),
 		newRemoveCommand(),
+		newSetCommand(),
 		newEditCommand(),
 		newSearchCommand(),
+		newSortCommand(),
+		newStatsCommand(),
+		newAgendaCommand(),
+		newStartCommand(),
+		newStopCommand(),
+		newReportCommand(),
+		newBlockedCommand(),
+		newNextCommand(),
+		newTagsCommand(),
+		newGraphCommand(),
+		newSnoozeCommand(),
+		newStaleCommand(),
+		newImportCommand(),
+		newExportCommand(),
+		newSyncCommand(),
+		newConfigCommand(),
 		newCompletionCommand(),
 	)
 
-	return rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return nil
+	}
+
+	jsonErrors = wantsJSONErrors(cmd)
+	if started {
+		return err
+	}
+	if !jsonErrors {
+		cmd.PrintErrln(cmd.UsageString())
+	}
+	if exitCode(err) == ExitError {
+		return withCode(ExitUsage, err)
+	}
+	return err
 }
 
 func newListCommand() *cobra.Command {
-	return &cobra.Command{
+	var (
+		minPriority  string
+		tags         []string
+		format       string
+		templateText string
+		templateFile string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List all tasks and sections with line numbers",
-		Long:  "List all tasks and sections in the markdown file with 1-based indexing for easy reference.",
+		Long: `List all tasks and sections in the markdown file with 1-based indexing for easy reference.
+Snoozed tasks are hidden until their date arrives unless --all is passed.
+With --min-priority only tasks at least that important are listed, and with --tag only tasks
+carrying all the given #tags or @mentions, along with their sections.
+--template renders every item through a Go text/template instead, see the README for the available fields and functions.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			writeRecords, err := lookupOutputFormat(format)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := loadFileConfig(filePath)
+			if err != nil {
+				return err
+			}
 			items, err := parseMarkdownFile(filePath)
 			if err != nil {
 				return err
 			}
+			dates := newDateContext(cfg)
+			hidden := hiddenItems(items, dates)
+
+			if minPriority != "" {
+				rank, err := parseMinPriority(minPriority)
+				if err != nil {
+					return err
+				}
+				for i, below := range belowPriority(items, rank) {
+					hidden[i] = hidden[i] || below
+				}
+			}
+			if len(tags) > 0 {
+				for i, item := range items {
+					hidden[i] = hidden[i] || (item.Type == TypeTask && !hasTags(item, tags))
+				}
+			}
+			if minPriority != "" || len(tags) > 0 {
+				hideEmptySections(items, hidden)
+			}
+
+			if templateText != "" || templateFile != "" {
+				tmpl, err := parseItemTemplate(templateText, templateFile, dates)
+				if err != nil {
+					return err
+				}
+				return writeTemplate(os.Stdout, tmpl, items, hidden)
+			}
+
+			if writeRecords != nil {
+				paths := sectionPaths(items)
+				var records []ItemRecord
+				for i, item := range items {
+					if !hidden[i] {
+						records = append(records, newItemRecord(item, i, paths[i]))
+					}
+				}
+				return writeRecords(os.Stdout, records)
+			}
+
+			if porcelain != "" {
+				paths := sectionPaths(items)
+				for i := range items {
+					if !hidden[i] {
+						printPorcelainItem(items, i, paths)
+					}
+				}
+				return nil
+			}
+
+			// Find the task being tracked, if any
+			entries, err := loadTimeLog(filePath)
+			if err != nil {
+				return err
+			}
+			runningIndex := -1
+			running := runningEntry(entries)
+			if running >= 0 {
+				runningIndex = findEntryTask(items, entries[running])
+			}
 
 			for i, item := range items {
-				fmt.Println(formatItem(item, i))
+				if hidden[i] {
+					continue
+				}
+				line := formatItem(item, i)
+				if i == runningIndex {
+					line += " " + formatRunning(entries[running])
+				}
+				fmt.Println(line)
 			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&minPriority, "min-priority", "", "Only list tasks with at least this priority (e.g. high, B, 2)")
+	cmd.Flags().StringSliceVarP(&tags, "tag", "t", nil, "Only list tasks with this #tag or @mention (repeatable)")
+	cmd.RegisterFlagCompletionFunc("tag", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeTags(toComplete)
+	})
+	addOutputFormatFlag(cmd, &format)
+	cmd.Flags().StringVar(&templateText, "template", "", "Render each item with this Go template, e.g. '{{.ID}} {{.Content}}'")
+	cmd.Flags().StringVar(&templateFile, "template-file", "", "Render each item with the Go template in this file")
+	cmd.MarkFlagsMutuallyExclusive("template", "template-file", "format")
+
+	return cmd
 }
 
 func newAddCommand() *cobra.Command {
@@ -486,12 +710,12 @@ func newAddCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			content := strings.Join(args, " ")
 			if content == "" {
-				return fmt.Errorf("content is required")
+				return errorf(ExitUsage, "content is required")
 			}
 
 			// Validate section level
 			if isSection && (sectionLevel < 1 || sectionLevel > 6) {
-				return fmt.Errorf("invalid section level %d (must be 1-6)", sectionLevel)
+				return errorf(ExitUsage, "invalid section level %d (must be 1-6)", sectionLevel)
 			}
 
 			// Create TaskManager and load items
@@ -504,7 +728,7 @@ func newAddCommand() *cobra.Command {
 			afterIndex := -1
 			if afterID > 0 {
 				if afterID > len(tm.Items) {
-					return fmt.Errorf("item ID %d does not exist (max: %d)", afterID, len(tm.Items))
+					return itemErrorf(ExitNotFound, afterID, "item ID %d does not exist (max: %d)", afterID, len(tm.Items))
 				}
 				afterIndex = afterID - 1 // Convert to 0-based
 			}
@@ -515,30 +739,76 @@ func newAddCommand() *cobra.Command {
 					return err
 				}
 
-				if afterID > 0 {
-					fmt.Printf("Added section after item %d: %s %s\n", afterID, strings.Repeat("#", sectionLevel), content)
-				} else {
-					fmt.Printf("Added section: %s %s\n", strings.Repeat("#", sectionLevel), content)
+				if porcelain == "" {
+					if afterID > 0 {
+						fmt.Printf("Added section after item %d: %s %s\n", afterID, strings.Repeat("#", sectionLevel), content)
+					} else {
+						fmt.Printf("Added section: %s %s\n", strings.Repeat("#", sectionLevel), content)
+					}
 				}
 			} else {
 				// Add a task
 				// Use parseTask to separate content from metadata
 				parsed := parseTask(fmt.Sprintf("- [ ] %s", content))
+				normalizePriority(parsed.Metadata)
+				if description, priority := extractPriorityShorthand(parsed.Description); priority != "" {
+					parsed.Description = description
+					if _, ok := parsed.Metadata["priority"]; !ok {
+						parsed.Metadata["priority"] = priority
+					}
+				}
+				dates := newDateContext(tm.Config)
+				if err := dates.Validate(parsed.Metadata); err != nil {
+					return err
+				}
+				if err := checkRecurRule(parsed.Metadata); err != nil {
+					return err
+				}
+				dates.Normalize(parsed.Metadata)
+				if tm.Config.Timestamps {
+					stampCreated(parsed.Metadata, dates)
+				}
 				if err := tm.AddTask(parsed.Description, parsed.Metadata, afterIndex); err != nil {
 					return err
 				}
+				index := len(tm.Items) - 1
+				if afterIndex >= 0 {
+					index = afterIndex + 1
+				}
+				tm.Items[index].EmojiKeys = parsed.EmojiKeys
+				if err := checkDependencyCycle(tm.Items, index); err != nil {
+					return err
+				}
 
-				if afterID > 0 {
-					fmt.Printf("Added task after item %d: %s\n", afterID, content)
-				} else {
-					fmt.Printf("Added task: %s\n", content)
+				if porcelain == "" {
+					if afterID > 0 {
+						fmt.Printf("Added task after item %d: %s\n", afterID, content)
+					} else {
+						fmt.Printf("Added task: %s\n", content)
+					}
 				}
 			}
 
+			// Apply the file's default sort, if any, unless the position was given
+			if len(tm.Config.Sort) > 0 && afterID == 0 {
+				sortItems(tm.Items, tm.Config.Sort)
+			}
+
 			// Save the changes
 			if err := tm.Save(); err != nil {
 				return fmt.Errorf("saving file: %w", err)
 			}
+
+			// The porcelain record gives the ID of the new item once sorted
+			if porcelain != "" {
+				// The new item is the only one not read from the file
+				index := slices.IndexFunc(tm.Items, func(item Item) bool { return item.LineNumber == 0 })
+				itemType := "task"
+				if isSection {
+					itemType = "section"
+				}
+				printPorcelain("added", index+1, itemType)
+			}
 			return nil
 		},
 	}
@@ -551,6 +821,8 @@ func newAddCommand() *cobra.Command {
 }
 
 func newDoneCommand() *cobra.Command {
+	var force bool
+
 	cmd := &cobra.Command{
 		Use:   "done <id>",
 		Short: "Mark task as completed",
@@ -569,19 +841,61 @@ func newDoneCommand() *cobra.Command {
 				return err
 			}
 
+			item, err := tm.GetItem(index)
+			if err != nil {
+				return err
+			}
+			wasCompleted := item.Checked != nil && *item.Checked
+
+			// Refuse to complete tasks still waiting on other tasks
+			if !wasCompleted && len(item.BlockedBy) > 0 {
+				if !force {
+					return itemErrorf(ExitConflict, id, "task %d is blocked by %s (use --force to complete it anyway)", id, strings.Join(item.BlockedBy, ", "))
+				}
+				fmt.Fprintf(os.Stderr, "Warning: completing task %d blocked by %s\n", id, strings.Join(item.BlockedBy, ", "))
+			}
+
 			if err := tm.ToggleTask(index, true); err != nil {
 				return err
 			}
 
+			dates := newDateContext(tm.Config)
+			if !wasCompleted && tm.Config.Timestamps {
+				if err := tm.SetMetadata(index, map[string]string{"completed": dates.today()}, nil); err != nil {
+					return err
+				}
+			}
+
+			// Add the next occurrence of recurring tasks
+			nextIndex := -1
+			if !wasCompleted {
+				if nextIndex, err = tm.RecurOrWarn(index, dates, os.Stderr); err != nil {
+					return err
+				}
+			}
+
 			if err := tm.Save(); err != nil {
 				return fmt.Errorf("saving file: %w", err)
 			}
 
+			if porcelain != "" {
+				printPorcelain("done", id)
+				if nextIndex >= 0 {
+					printPorcelain("added", nextIndex+1, "task")
+				}
+				return nil
+			}
+
 			fmt.Printf("Marked task %d as completed\n", id)
+			if nextIndex >= 0 {
+				fmt.Printf("Added next occurrence as task %d due %s\n", nextIndex+1, tm.Items[nextIndex].Metadata["due"])
+			}
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Complete the task even if it is blocked")
+
 	// Add completion for task IDs
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completeTaskIDs(toComplete, false) // false = incomplete tasks only
@@ -613,10 +927,20 @@ func newUndoCommand() *cobra.Command {
 				return err
 			}
 
+			// The task is no longer completed
+			if err := tm.SetMetadata(index, nil, []string{"completed"}); err != nil {
+				return err
+			}
+
 			if err := tm.Save(); err != nil {
 				return fmt.Errorf("saving file: %w", err)
 			}
 
+			if porcelain != "" {
+				printPorcelain("undone", id)
+				return nil
+			}
+
 			fmt.Printf("Marked task %d as incomplete\n", id)
 			return nil
 		},
@@ -704,6 +1028,11 @@ func newRemoveCommand() *cobra.Command {
 				return fmt.Errorf("saving file: %w", err)
 			}
 
+			if porcelain != "" {
+				printPorcelain("removed", id, itemType)
+				return nil
+			}
+
 			fmt.Printf("Removed %s %d: %s\n", itemType, id, itemContent)
 			return nil
 		},
@@ -719,9 +1048,93 @@ func newRemoveCommand() *cobra.Command {
 	return cmd
 }
 
-// confirmRemoval prompts the user for confirmation before removing an item
+func newSetCommand() *cobra.Command {
+	var unset []string
+
+	cmd := &cobra.Command{
+		Use:   "set <id> [key:value...]",
+		Short: "Set or remove task metadata",
+		Long: `Set or remove metadata on a task by specifying its ID.
+Relative dates such as due:tomorrow or due:friday are saved as ISO dates.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Parse the ID
+			index, err := parseItemID(args[0])
+			if err != nil {
+				return err
+			}
+			id := index + 1 // Keep original ID for display
+
+			// Use parseTask to extract the key:value pairs
+			parsed := parseTask(fmt.Sprintf("- [ ] %s", strings.Join(args[1:], " ")))
+			if parsed.Description != "" {
+				return errorf(ExitParse, "expected key:value pairs, got '%s'", parsed.Description)
+			}
+			if len(parsed.Metadata) == 0 && len(unset) == 0 {
+				return errorf(ExitUsage, "nothing to set")
+			}
+
+			tm, err := NewTaskManager(filePath)
+			if err != nil {
+				return err
+			}
+
+			dates := newDateContext(tm.Config)
+			if err := dates.Validate(parsed.Metadata); err != nil {
+				return err
+			}
+			if err := checkRecurRule(parsed.Metadata); err != nil {
+				return err
+			}
+			dates.Normalize(parsed.Metadata)
+			if _, ok := parsed.Metadata["p"]; ok {
+				// Replace any existing short alias along with the priority
+				normalizePriority(parsed.Metadata)
+				unset = append(unset, "p")
+			}
+			if err := tm.SetMetadata(index, parsed.Metadata, unset); err != nil {
+				return err
+			}
+			if err := checkDependencyCycle(tm.Items, index); err != nil {
+				return err
+			}
+
+			if err := tm.Save(); err != nil {
+				return fmt.Errorf("saving file: %w", err)
+			}
+
+			if porcelain != "" {
+				printPorcelain("updated", id)
+				return nil
+			}
+
+			item, _ := tm.GetItem(index)
+			fmt.Printf("Updated task %d: %s\n", id, item.Content)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&unset, "unset", "u", nil, "Metadata keys to remove")
+
+	// Add completion for task IDs
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeItemIDs(toComplete, ItemFilter{IncludeTasks: true})
+	}
+
+	return cmd
+}
+
+// confirmRemoval prompts the user for confirmation before removing an item.
+// The prompt goes to stderr in porcelain mode to keep stdout for records.
 func confirmRemoval(itemDesc string) (bool, error) {
-	fmt.Printf("Remove %s? [y/N] ", itemDesc)
+	prompt := os.Stdout
+	if porcelain != "" {
+		prompt = os.Stderr
+	}
+	fmt.Fprintf(prompt, "Remove %s? [y/N] ", itemDesc)
 
 	reader := bufio.NewReader(os.Stdin)
 	input, err := reader.ReadString('\n')
@@ -795,6 +1208,11 @@ func newEditCommand() *cobra.Command {
 				return fmt.Errorf("running editor: %w", err)
 			}
 
+			if porcelain != "" {
+				printPorcelain("edited", id)
+				return nil
+			}
+
 			fmt.Printf("Edited item %d with %s\n", id, editor)
 			return nil
 		},
@@ -809,12 +1227,26 @@ func newEditCommand() *cobra.Command {
 }
 
 func newSearchCommand() *cobra.Command {
-	return &cobra.Command{
+	var (
+		tags   []string
+		format string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "search [terms...]",
 		Short: "Search tasks and sections",
-		Long:  "Search tasks and sections with fuzzy matching. Multiple search terms can be provided.",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Search tasks and sections with fuzzy matching. Multiple search terms can be provided.
+With --tag only tasks carrying all the given #tags or @mentions are matched; terms are then optional.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && len(tags) == 0 {
+				return errorf(ExitUsage, "requires at least 1 search term or --tag")
+			}
+
+			writeRecords, err := lookupOutputFormat(format)
+			if err != nil {
+				return err
+			}
+
 			// Load items from file
 			items, err := parseMarkdownFile(filePath)
 			if err != nil {
@@ -822,7 +1254,39 @@ func newSearchCommand() *cobra.Command {
 			}
 
 			// Perform search
-			results := searchItems(items, args)
+			var results []SearchResult
+			if len(args) > 0 {
+				results = searchItems(items, args)
+			} else {
+				for i, item := range items {
+					results = append(results, SearchResult{Item: item, Index: i})
+				}
+			}
+			if len(tags) > 0 {
+				results = slices.DeleteFunc(results, func(result SearchResult) bool {
+					return !hasTags(result.Item, tags)
+				})
+				for _, tag := range tags {
+					args = append(args, normalizeTag(tag))
+				}
+			}
+
+			if writeRecords != nil {
+				paths := sectionPaths(items)
+				records := make([]ItemRecord, len(results))
+				for i, result := range results {
+					records[i] = newItemRecord(result.Item, result.Index, paths[result.Index])
+				}
+				return writeRecords(os.Stdout, records)
+			}
+
+			if porcelain != "" {
+				paths := sectionPaths(items)
+				for _, result := range results {
+					printPorcelainItem(items, result.Index, paths)
+				}
+				return nil
+			}
 
 			if len(results) == 0 {
 				fmt.Printf("No matches found for: %s\n", strings.Join(args, " "))
@@ -839,6 +1303,14 @@ func newSearchCommand() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().StringSliceVarP(&tags, "tag", "t", nil, "Only match tasks with this #tag or @mention (repeatable)")
+	cmd.RegisterFlagCompletionFunc("tag", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeTags(toComplete)
+	})
+	addOutputFormatFlag(cmd, &format)
+
+	return cmd
 }
 
 func newCompletionCommand() *cobra.Command {
@@ -947,10 +1419,18 @@ func completeItemIDs(toComplete string, filter ItemFilter) ([]string, cobra.Shel
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
+	// Hide snoozed tasks; a broken config only means they are shown
+	cfg, _ := loadFileConfig(filePath)
+	hidden := hiddenItems(items, newDateContext(cfg))
+
 	var completions []string
 	includeTypePrefix := filter.IncludeTasks && filter.IncludeSections
 
 	for i, item := range items {
+		if hidden[i] {
+			continue
+		}
+
 		// Filter by item type
 		if item.Type == TypeTask && !filter.IncludeTasks {
 			continue
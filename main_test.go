@@ -181,6 +181,25 @@ func TestDeleteItem_Section(t *testing.T) {
 	require.Equal(t, "Task 3", result[1].Content)
 }
 
+func TestSectionPaths(t *testing.T) {
+	items := []Item{
+		{Type: TypeTask, Content: "Loose task"},
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		{Type: TypeSection, Level: 2, Content: "Backend"},
+		{Type: TypeTask, Content: "API"},
+		{Type: TypeSection, Level: 2, Content: "Frontend"},
+		{Type: TypeSection, Level: 1, Content: "Home"},
+		{Type: TypeTask, Content: "Dishes"},
+	}
+
+	paths := sectionPaths(items)
+	require.Empty(t, paths[0])
+	require.Equal(t, []string{"Work"}, paths[1])
+	require.Equal(t, []string{"Work", "Backend"}, paths[3])
+	require.Equal(t, []string{"Work", "Frontend"}, paths[4])
+	require.Equal(t, []string{"Home"}, paths[6])
+}
+
 func TestGetVersion(t *testing.T) {
 	version := getVersion()
 	require.NotEmpty(t, version)
@@ -938,7 +957,7 @@ func TestSaveToFile_MetadataQuoting(t *testing.T) {
 	require.Contains(t, fileContent, "nospace:value")
 	require.Contains(t, fileContent, `"value with spaces"`)
 	require.Contains(t, fileContent, `"value with \"quotes\""`)
-	require.Contains(t, fileContent, "withcolon:value:with:colons")
+	require.Contains(t, fileContent, `withcolon:"value:with:colons"`)
 }
 
 // Tests for parseItemID function (currently 0% coverage)
@@ -1054,6 +1073,25 @@ func TestFormatItem(t *testing.T) {
 		}
 	})
 
+	t.Run("due date highlighting", func(t *testing.T) {
+		oldColorMode := colorMode
+		colorMode = "always"
+		t.Cleanup(func() { colorMode = oldColorMode })
+
+		task := func(due string) Item {
+			return Item{
+				Type:     TypeTask,
+				Content:  "Task",
+				Checked:  func() *bool { b := false; return &b }(),
+				Metadata: map[string]string{"due": due},
+			}
+		}
+
+		require.Contains(t, formatItem(task("yesterday"), 0), "\033[1;91mdue:yesterday")
+		require.Contains(t, formatItem(task("today"), 0), "\033[1;93mdue:today")
+		require.Contains(t, formatItem(task("2999-01-01"), 0), "\033[32mdue:2999-01-01")
+	})
+
 	t.Run("terminal vs non-terminal formatting", func(t *testing.T) {
 		// Test both terminal and non-terminal output
 		// Since isTerminal() depends on actual terminal state, we test the logic paths
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// The Obsidian Tasks plugin stores task fields as emoji signifiers at the end of the line:
+//
+//	- [x] Water plants ⏫ 🔁 every week 📅 2025-08-13 ✅ 2025-08-13
+//
+// They are read as the equivalent metadata and written back in the same syntax,
+// so files edited with both tools keep their annotations.
+
+// emojiField describes an Obsidian Tasks field and the metadata key it maps to
+type emojiField struct {
+	Key    string
+	Emoji  []string // The first one is written, the others are accepted when reading
+	Value  string   // Fixed metadata value (priorities), empty when the emoji is followed by a value
+	Phrase bool     // The value spans several words (recurrence rules)
+	Date   bool     // The value is a date
+}
+
+// emojiFields lists the supported fields in the order the plugin writes them
+var emojiFields = []emojiField{
+	{Key: "priority", Emoji: []string{"🔺"}, Value: "highest"},
+	{Key: "priority", Emoji: []string{"⏫"}, Value: "high"},
+	{Key: "priority", Emoji: []string{"🔼"}, Value: "medium"},
+	{Key: "priority", Emoji: []string{"🔽"}, Value: "low"},
+	{Key: "priority", Emoji: []string{"⏬"}, Value: "lowest"},
+	{Key: "recur", Emoji: []string{"🔁"}, Phrase: true},
+	{Key: "after", Emoji: []string{"⛔"}},
+	{Key: "id", Emoji: []string{"🆔"}},
+	{Key: "created", Date: true, Emoji: []string{"➕"}},
+	{Key: "start", Date: true, Emoji: []string{"🛫"}},
+	{Key: "scheduled", Date: true, Emoji: []string{"⏳", "⌛"}},
+	{Key: "due", Date: true, Emoji: []string{"📅", "📆", "🗓"}},
+	{Key: "cancelled", Date: true, Emoji: []string{"❌"}},
+	{Key: "completed", Date: true, Emoji: []string{"✅"}},
+}
+
+// variationSelector may follow an emoji to request its emoji presentation
+const variationSelector = "\uFE0F"
+
+// emojiKeys lists the metadata keys that can be written as emoji fields, in writing order
+func emojiKeys() []string {
+	var keys []string
+	for _, field := range emojiFields {
+		if !slices.Contains(keys, field.Key) {
+			keys = append(keys, field.Key)
+		}
+	}
+	return keys
+}
+
+// matchEmoji returns the field whose emoji starts s and the length of the emoji
+func matchEmoji(s string) (emojiField, int, bool) {
+	for _, field := range emojiFields {
+		for _, emoji := range field.Emoji {
+			if strings.HasPrefix(s, emoji) {
+				n := len(emoji)
+				if strings.HasPrefix(s[n:], variationSelector) {
+					n += len(variationSelector)
+				}
+				return field, n, true
+			}
+		}
+	}
+	return emojiField{}, 0, false
+}
+
+// parseEmojiField tries to parse an Obsidian Tasks field such as "📅 2025-08-13" or "⏫"
+func (p *TaskParser) parseEmojiField() (key, value string, ok bool) {
+	start := p.pos
+
+	field, n, ok := matchEmoji(p.input[p.pos:])
+	if !ok {
+		return "", "", false
+	}
+	p.pos += n
+
+	if field.Value != "" {
+		return field.Key, field.Value, true
+	}
+
+	p.skipWhitespace()
+	if !field.Phrase {
+		value = p.parseWord()
+	} else {
+		// The phrase ends at the next emoji field or key:value pair
+		var words []string
+		for p.pos < p.len {
+			wordStart := p.pos
+			if _, _, ok := matchEmoji(p.input[p.pos:]); ok {
+				break
+			}
+			if _, _, ok := p.parseMetadata(); ok {
+				p.pos = wordStart
+				break
+			}
+			words = append(words, p.parseWord())
+			p.skipWhitespace()
+		}
+		value = strings.Join(words, " ")
+	}
+
+	if value == "" {
+		p.pos = start
+		return "", "", false
+	}
+	if field.Date {
+		// Relative dates are accepted so they can be given to add and set
+		if _, ok := newDateContext(FileConfig{}).Parse(value); !ok {
+			p.pos = start
+			return "", "", false
+		}
+	}
+	return field.Key, value, true
+}
+
+// formatEmojiField formats metadata as an Obsidian Tasks field, if it can be represented as one
+func formatEmojiField(key, value string) (string, bool) {
+	if key == "priority" {
+		rank, ok := priorityRank(value)
+		if !ok {
+			return "", false
+		}
+		for _, field := range emojiFields {
+			if fieldRank, _ := priorityRank(field.Value); field.Key == key && fieldRank == min(rank, 4) {
+				return field.Emoji[0], true
+			}
+		}
+		return "", false
+	}
+
+	for _, field := range emojiFields {
+		if field.Key != key {
+			continue
+		}
+		if value == "" || (!field.Phrase && strings.ContainsAny(value, " \t")) {
+			return "", false
+		}
+		return field.Emoji[0] + " " + value, true
+	}
+	return "", false
+}
+
+// formatTaskMetadata formats the metadata of a task for saving: key:value pairs in key order,
+// followed by the keys read in emoji syntax, in the order Obsidian Tasks writes them
+func formatTaskMetadata(item Item) string {
+	var parts []string
+	var emoji []string
+
+	for _, key := range emojiKeys() {
+		if value, ok := item.Metadata[key]; ok && slices.Contains(item.EmojiKeys, key) {
+			if field, ok := formatEmojiField(key, value); ok {
+				emoji = append(emoji, field)
+			}
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(item.Metadata)) {
+		value := item.Metadata[key]
+		if slices.Contains(item.EmojiKeys, key) {
+			if _, ok := formatEmojiField(key, value); ok {
+				continue
+			}
+		}
+		// Quote values that would not parse back unquoted (spaces, commas, quotes...)
+		parts = append(parts, key+":"+formatMetadataValue(value))
+	}
+
+	return strings.Join(append(parts, emoji...), " ")
+}
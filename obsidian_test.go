@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTask_EmojiFields(t *testing.T) {
+	testCases := []struct {
+		input       string
+		description string
+		metadata    map[string]string
+		emojiKeys   []string
+	}{
+		{
+			"- [ ] Water plants ⏫ 🔁 every week 📅 2025-08-13",
+			"Water plants",
+			map[string]string{"priority": "high", "recur": "every week", "due": "2025-08-13"},
+			[]string{"priority", "recur", "due"},
+		},
+		{
+			"- [x] Pay rent 📅 2025-08-01 ✅ 2025-08-02",
+			"Pay rent",
+			map[string]string{"due": "2025-08-01", "completed": "2025-08-02"},
+			[]string{"due", "completed"},
+		},
+		{
+			"- [ ] Mixed owner:bob 🗓️ 2025-09-01 🔁 every 2 weeks id:x",
+			"Mixed",
+			map[string]string{"owner": "bob", "due": "2025-09-01", "recur": "every 2 weeks", "id": "x"},
+			[]string{"due", "recur"},
+		},
+		{
+			"- [ ] Deploy 🆔 deploy ⛔ build,test 🔽",
+			"Deploy",
+			map[string]string{"id": "deploy", "after": "build,test", "priority": "low"},
+			[]string{"id", "after", "priority"},
+		},
+		{
+			"- [ ] Not a field ➕ stuff",
+			"Not a field ➕ stuff",
+			map[string]string{},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		result := parseTask(tc.input)
+		require.Equal(t, tc.description, result.Description, "Input: %s", tc.input)
+		require.Equal(t, tc.metadata, result.Metadata, "Input: %s", tc.input)
+		require.Equal(t, tc.emojiKeys, result.EmojiKeys, "Input: %s", tc.input)
+	}
+}
+
+func TestFormatEmojiField(t *testing.T) {
+	testCases := []struct {
+		key      string
+		value    string
+		expected string
+		ok       bool
+	}{
+		{"due", "2025-08-13", "📅 2025-08-13", true},
+		{"recur", "every week", "🔁 every week", true},
+		{"priority", "high", "⏫", true},
+		{"priority", "A", "🔺", true},
+		{"priority", "9", "⏬", true},
+		{"priority", "someday", "", false},
+		{"due", "not a date", "", false},
+		{"owner", "bob", "", false},
+	}
+
+	for _, tc := range testCases {
+		field, ok := formatEmojiField(tc.key, tc.value)
+		require.Equal(t, tc.ok, ok, "Key: %s, value: %s", tc.key, tc.value)
+		require.Equal(t, tc.expected, field, "Key: %s, value: %s", tc.key, tc.value)
+	}
+}
+
+func TestSaveToFile_EmojiRoundTrip(t *testing.T) {
+	content := `# Garden
+
+- [ ] Water plants ⏫ 🔁 every week 📅 2025-08-13
+- [x] Mixed task owner:bob ➕ 2025-08-01 📅 2025-09-01 ✅ 2025-09-01
+- [ ] Plain task due:2025-08-20
+`
+	filename := createTestFile(t, content)
+
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+	require.NoError(t, saveToFile(filename, items))
+
+	saved, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, string(saved))
+}
+
+func TestTaskManager_SetMetadata_EmojiKeys(t *testing.T) {
+	filename := createTestFile(t, "- [ ] Water plants 📅 2025-08-13 ⏫\n- [ ] Plain task\n")
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+
+	require.NoError(t, tm.SetMetadata(0, map[string]string{"completed": "2025-08-13", "owner": "bob"}, []string{"priority"}))
+	require.NoError(t, tm.SetMetadata(1, map[string]string{"due": "2025-08-15"}, nil))
+	require.NoError(t, tm.Save())
+
+	saved, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "- [ ] Water plants owner:bob 📅 2025-08-13 ✅ 2025-08-13\n- [ ] Plain task due:2025-08-15\n", string(saved))
+}
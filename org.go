@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Org-mode files keep tasks in headlines, with their dates and properties on the following lines:
+//
+//	* Work
+//	** TODO [#A] Fix the build :ci:
+//	DEADLINE: <2025-08-15 Fri +1w> SCHEDULED: <2025-08-10 Sun>
+//	:PROPERTIES:
+//	:ID: build
+//	:END:
+//	- [ ] Update the runner
+//
+// Headlines with a TODO keyword are tasks and the others are sections. Checkboxes are subtasks
+// of the headline above them. Markdown tasks have no body, so other text is dropped on import.
+
+// orgKeywords lists the TODO keywords of an org file, as declared by #+TODO: lines
+type orgKeywords struct {
+	open []string
+	done []string
+}
+
+// defaultOrgKeywords are the keywords of files without #+TODO: lines
+var defaultOrgKeywords = orgKeywords{open: []string{"TODO"}, done: []string{"DONE"}}
+
+var (
+	orgHeadlineRegex = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	orgCheckboxRegex = regexp.MustCompile(`^(\s*)(?:[-+*]|\d+[.)])\s+\[([ xX-])\]\s+(.*)$`)
+	orgPriorityRegex = regexp.MustCompile(`^\[#([A-Z0-9])\]\s*`)
+	orgTagsRegex     = regexp.MustCompile(`\s+:([\p{L}\p{N}_@#%:]+):\s*$`)
+	orgCookieRegex   = regexp.MustCompile(`\s*\[\d*(?:/\d*|%)\]`)
+	orgPlanningRegex = regexp.MustCompile(`(DEADLINE|SCHEDULED|CLOSED):\s*([<\[][^>\]]*[>\]])`)
+	orgPropertyRegex = regexp.MustCompile(`^\s*:([^:\s]+):\s*(.*)$`)
+	orgDateRegex     = regexp.MustCompile(`^[<\[](\d{4}-\d{2}-\d{2})[^>\]]*[>\]]$`)
+	orgRepeaterRegex = regexp.MustCompile(`\s[.+]?\+(\d+)([dwmy])[\s>\]]`)
+)
+
+// orgPlanningKeys maps planning keywords to metadata keys
+var orgPlanningKeys = map[string]string{
+	"DEADLINE":  "due",
+	"SCHEDULED": "scheduled",
+	"CLOSED":    "completed",
+}
+
+// parseOrgKeywords parses the value of a #+TODO: line such as "TODO NEXT(n) | DONE CANCELED(c)".
+// Without "|", the last keyword is the done one.
+func parseOrgKeywords(value string) orgKeywords {
+	var keywords orgKeywords
+	words := strings.Fields(value)
+	separator := slices.Index(words, "|")
+	if separator < 0 && len(words) > 0 {
+		separator = len(words) - 1
+		words = slices.Insert(words, separator, "|")
+	}
+
+	for i, word := range words {
+		if word == "|" {
+			continue
+		}
+		word, _, _ = strings.Cut(word, "(")
+		if i < separator {
+			keywords.open = append(keywords.open, word)
+		} else {
+			keywords.done = append(keywords.done, word)
+		}
+	}
+	return keywords
+}
+
+// parseOrgDate converts an org timestamp such as <2025-08-15 Fri +1w> to a date and a recur rule, if it repeats
+func parseOrgDate(value string) (string, string, bool) {
+	matches := orgDateRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return "", "", false
+	}
+	var recur string
+	if repeater := orgRepeaterRegex.FindStringSubmatch(value); repeater != nil {
+		recur = "every-" + repeater[1] + repeater[2]
+	}
+	return matches[1], recur, true
+}
+
+// orgHeadline is a headline split into its parts
+type orgHeadline struct {
+	Stars    int
+	Keyword  string // Empty for sections
+	Done     bool
+	Priority string
+	Title    string // With the tags as #tag words
+}
+
+// parseOrgHeadline splits a headline, the keywords telling tasks from sections
+func parseOrgHeadline(stars int, text string, keywords orgKeywords) orgHeadline {
+	headline := orgHeadline{Stars: stars}
+
+	word, rest, _ := strings.Cut(text, " ")
+	switch {
+	case slices.Contains(keywords.open, word):
+		headline.Keyword, text = word, rest
+	case slices.Contains(keywords.done, word):
+		headline.Keyword, headline.Done, text = word, true, rest
+	}
+	text = strings.TrimSpace(text)
+
+	if matches := orgPriorityRegex.FindStringSubmatch(text); matches != nil {
+		headline.Priority = matches[1]
+		text = text[len(matches[0]):]
+	}
+
+	var tags []string
+	if matches := orgTagsRegex.FindStringSubmatch(text); matches != nil {
+		text = text[:len(text)-len(matches[0])]
+		for tag := range strings.SplitSeq(matches[1], ":") {
+			if tag = normalizeTag(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	title := strings.Join(strings.Fields(orgCookieRegex.ReplaceAllString(text, "")), " ")
+	existing := parseTags(title)
+	for _, tag := range tags {
+		if !slices.Contains(existing, tag) {
+			title += " " + tag
+			existing = append(existing, tag)
+		}
+	}
+	headline.Title = strings.TrimSpace(title)
+	return headline
+}
+
+// taskPriorityFromOrg converts an org priority cookie to a task priority
+func taskPriorityFromOrg(value string) string {
+	switch value {
+	case "A":
+		return "high"
+	case "B":
+		return "medium"
+	case "C":
+		return "low"
+	}
+	return value
+}
+
+// importOrg reads the headlines and checkboxes of an org file, keeping the hierarchy:
+// headline levels become section levels and nested tasks are indented under their parent task
+func importOrg(r io.Reader) ([]Item, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading org file: %w", err)
+	}
+
+	// #+TODO: lines apply to the whole file
+	var keywords orgKeywords
+	for _, line := range lines {
+		upper := strings.ToUpper(line)
+		for _, prefix := range []string{"#+TODO:", "#+SEQ_TODO:", "#+TYP_TODO:"} {
+			if strings.HasPrefix(upper, prefix) {
+				declared := parseOrgKeywords(line[len(prefix):])
+				keywords.open = append(keywords.open, declared.open...)
+				keywords.done = append(keywords.done, declared.done...)
+			}
+		}
+	}
+	if len(keywords.open)+len(keywords.done) == 0 {
+		keywords = defaultOrgKeywords
+	}
+
+	// key:value pairs of the titles of tasks, by index, added once their properties are known
+	titleMetadata := make(map[int]map[string]string)
+
+	var items []Item
+	var taskStars []int // Stars of the task headlines enclosing the current line
+	var indents []int   // Indentation of the checkboxes enclosing the current line
+	current := -1       // Index of the task of the current headline, -1 under a section
+	inHeader := false   // Whether planning lines and drawers may still follow the headline
+	inDrawer := ""      // Name of the drawer being read
+	for _, line := range lines {
+		if matches := orgHeadlineRegex.FindStringSubmatch(line); matches != nil {
+			headline := parseOrgHeadline(len(matches[1]), matches[2], keywords)
+			for len(taskStars) > 0 && taskStars[len(taskStars)-1] >= headline.Stars {
+				taskStars = taskStars[:len(taskStars)-1]
+			}
+			indents, inHeader, inDrawer = nil, true, ""
+
+			if headline.Keyword == "" {
+				taskStars, current = nil, -1
+				items = append(items, Item{Type: TypeSection, Level: min(headline.Stars, maxSectionLevel), Content: headline.Title})
+				continue
+			}
+
+			metadata := make(map[string]string)
+			if headline.Priority != "" {
+				metadata["priority"] = taskPriorityFromOrg(headline.Priority)
+			}
+			done := headline.Done
+			title, pairs := parseTitle(headline.Title)
+			titleMetadata[len(items)] = pairs
+			items = append(items, Item{
+				Type:     TypeTask,
+				Level:    2 * len(taskStars),
+				Content:  title,
+				Checked:  &done,
+				Metadata: metadata,
+				Tags:     parseTags(title),
+			})
+			current = len(items) - 1
+			taskStars = append(taskStars, headline.Stars)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if inDrawer != "" {
+			if strings.EqualFold(trimmed, ":END:") {
+				inDrawer = ""
+				continue
+			}
+			if matches := orgPropertyRegex.FindStringSubmatch(line); matches != nil && inDrawer == "PROPERTIES" && current >= 0 {
+				key := strings.ToLower(matches[1])
+				value := strings.TrimSpace(matches[2])
+				if date, _, ok := parseOrgDate(value); ok {
+					value = date
+				}
+				if _, exists := items[current].Metadata[key]; isIdentifier(key) && value != "" && !exists {
+					items[current].Metadata[key] = value
+				}
+			}
+			continue
+		}
+
+		if inHeader {
+			if planning := orgPlanningRegex.FindAllStringSubmatch(line, -1); planning != nil {
+				for _, matches := range planning {
+					date, recur, ok := parseOrgDate(matches[2])
+					if !ok || current < 0 {
+						continue
+					}
+					key := orgPlanningKeys[matches[1]]
+					if key == "completed" && !*items[current].Checked {
+						continue
+					}
+					items[current].Metadata[key] = date
+					if recur != "" && key == "due" {
+						items[current].Metadata["recur"] = recur
+					}
+				}
+				continue
+			}
+			if len(trimmed) > 2 && strings.HasPrefix(trimmed, ":") && strings.HasSuffix(trimmed, ":") {
+				inDrawer = strings.ToUpper(strings.Trim(trimmed, ":"))
+				continue
+			}
+			inHeader = trimmed == ""
+		}
+
+		if matches := orgCheckboxRegex.FindStringSubmatch(line); matches != nil {
+			indent := len(matches[1])
+			for len(indents) > 0 && indents[len(indents)-1] >= indent {
+				indents = indents[:len(indents)-1]
+			}
+			level := 2 * len(indents)
+			if current >= 0 {
+				level += items[current].Level + 2
+			}
+			indents = append(indents, indent)
+
+			content, pairs := parseTitle(strings.Join(strings.Fields(matches[3]), " "))
+			titleMetadata[len(items)] = pairs
+			done := matches[2] == "x" || matches[2] == "X"
+			items = append(items, Item{
+				Type:    TypeTask,
+				Level:   level,
+				Content: content,
+				Checked: &done,
+				Tags:    parseTags(content),
+			})
+		}
+	}
+
+	// Properties and planning lines take precedence over the key:value pairs of titles
+	for i, pairs := range titleMetadata {
+		for key, value := range pairs {
+			if _, ok := items[i].Metadata[key]; !ok {
+				if items[i].Metadata == nil {
+					items[i].Metadata = make(map[string]string)
+				}
+				items[i].Metadata[key] = value
+			}
+		}
+	}
+	for i := range items {
+		if items[i].Type == TypeTask && len(items[i].Metadata) == 0 {
+			items[i].Metadata = nil
+		}
+	}
+	return items, nil
+}
+
+// orgPriority converts a priority to an org priority cookie letter, if possible.
+// Letters are kept, other priorities map to the default A, B and C range.
+func orgPriority(value string) (string, bool) {
+	rank, ok := priorityRank(value)
+	switch {
+	case !ok || rank < 0:
+		return "", false
+	case len(value) == 1 && rank <= 25 && value[0] > '9':
+		return strings.ToUpper(value), true
+	case rank <= 1:
+		return "A", true
+	case rank == 2:
+		return "B", true
+	default:
+		return "C", true
+	}
+}
+
+// formatOrgDate formats a metadata date as an org timestamp, active (<...>) or inactive ([...])
+func formatOrgDate(value string, active bool, repeater string) (string, bool) {
+	date, ok := newDateContext(FileConfig{}).Parse(value)
+	if !ok {
+		return "", false
+	}
+	stamp := date.Format("2006-01-02 Mon")
+	if repeater != "" {
+		stamp += " " + repeater
+	}
+	if active {
+		return "<" + stamp + ">", true
+	}
+	return "[" + stamp + "]", true
+}
+
+// formatOrgTask returns the lines of a task as an org headline with the given number of stars
+func formatOrgTask(item Item, stars int) []string {
+	metadata := maps.Clone(item.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	done := item.Checked != nil && *item.Checked
+
+	headline := strings.Repeat("*", stars) + " TODO"
+	if done {
+		headline = strings.Repeat("*", stars) + " DONE"
+	}
+	if value, ok := metadataValue(item, "priority"); ok {
+		if priority, ok := orgPriority(value); ok {
+			headline += " [#" + priority + "]"
+			delete(metadata, "priority")
+			delete(metadata, "p")
+		}
+	}
+
+	// Tags go at the end of the headline
+	var words []string
+	for _, word := range strings.Fields(item.Content) {
+		if tags := parseTags(word); len(tags) == 1 && tags[0] == strings.ToLower(word) {
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(words) == 0 {
+		words = []string{item.Content}
+	}
+	headline += " " + strings.Join(words, " ")
+	if len(item.Tags) > 0 {
+		var tags []string
+		for _, tag := range item.Tags {
+			tags = append(tags, strings.TrimPrefix(tag, "#"))
+		}
+		headline += " :" + strings.Join(tags, ":") + ":"
+	}
+	lines := []string{headline}
+
+	var repeater string
+	// Rules on given weekdays or counting from completion have no plain repeater and stay a property
+	if rule, err := parseRecurRule(metadata["recur"]); err == nil && len(rule.Weekdays) == 0 && !rule.WhenDone {
+		if _, hasDue := metadata["due"]; hasDue {
+			repeater = fmt.Sprintf("+%d%c", rule.Every, rule.Unit)
+			delete(metadata, "recur")
+		}
+	}
+
+	var planning []string
+	for _, keyword := range []string{"CLOSED", "DEADLINE", "SCHEDULED"} {
+		key := orgPlanningKeys[keyword]
+		if key == "completed" && !done {
+			continue
+		}
+		value, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		rep := ""
+		if key == "due" {
+			rep = repeater
+		}
+		if stamp, ok := formatOrgDate(value, key != "completed", rep); ok {
+			planning = append(planning, keyword+": "+stamp)
+			delete(metadata, key)
+		}
+	}
+	if len(planning) > 0 {
+		lines = append(lines, strings.Join(planning, " "))
+	}
+
+	if len(metadata) > 0 {
+		lines = append(lines, ":PROPERTIES:")
+		for _, key := range slices.Sorted(maps.Keys(metadata)) {
+			lines = append(lines, ":"+strings.ToUpper(key)+": "+strings.Join(strings.Fields(metadata[key]), " "))
+		}
+		lines = append(lines, ":END:")
+	}
+
+	return lines
+}
+
+// exportOrg writes sections as headlines and tasks as TODO or DONE headlines below them,
+// subtasks being nested one level deeper than their parent task
+func exportOrg(w io.Writer, items []Item) error {
+	sectionLevel := 0
+	var levels []int // Indentation of the tasks enclosing the current one
+	for _, item := range items {
+		var lines []string
+		if item.Type == TypeSection {
+			sectionLevel, levels = item.Level, nil
+			lines = []string{strings.Repeat("*", item.Level) + " " + item.Content}
+		} else {
+			for len(levels) > 0 && levels[len(levels)-1] >= item.Level {
+				levels = levels[:len(levels)-1]
+			}
+			lines = formatOrgTask(item, sectionLevel+1+len(levels))
+			levels = append(levels, item.Level)
+		}
+
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrgKeywords(t *testing.T) {
+	require.Equal(t, orgKeywords{open: []string{"TODO", "NEXT"}, done: []string{"DONE", "CANCELED"}}, parseOrgKeywords(" TODO(t) NEXT(n) | DONE(d!) CANCELED(c@)"))
+	require.Equal(t, orgKeywords{open: []string{"TODO", "WAIT"}, done: []string{"FINISHED"}}, parseOrgKeywords("TODO WAIT FINISHED"))
+}
+
+func TestParseOrgDate(t *testing.T) {
+	tests := []struct {
+		value string
+		date  string
+		recur string
+		ok    bool
+	}{
+		{"<2025-08-15 Fri>", "2025-08-15", "", true},
+		{"[2025-08-15 Fri 10:30]", "2025-08-15", "", true},
+		{"<2025-08-15 Fri +1w>", "2025-08-15", "every-1w", true},
+		{"<2025-08-15 Fri 09:00 .+2d -1d>", "2025-08-15", "every-2d", true},
+		{"<2025-08-15>", "2025-08-15", "", true},
+		{"2025-08-15", "", "", false},
+		{"<tomorrow>", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			date, recur, ok := parseOrgDate(tt.value)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.date, date)
+			require.Equal(t, tt.recur, recur)
+		})
+	}
+}
+
+func TestParseOrgHeadline(t *testing.T) {
+	keywords := orgKeywords{open: []string{"TODO", "NEXT"}, done: []string{"DONE"}}
+
+	require.Equal(t, orgHeadline{Stars: 2, Keyword: "TODO", Priority: "A", Title: "Fix the build #ci @bob"},
+		parseOrgHeadline(2, "TODO [#A] Fix the build [1/3]   :ci:@bob:", keywords))
+	require.Equal(t, orgHeadline{Stars: 1, Keyword: "DONE", Done: true, Title: "Pay rent #bills"},
+		parseOrgHeadline(1, "DONE Pay rent #bills :bills:", keywords))
+	require.Equal(t, orgHeadline{Stars: 1, Title: "Projects"}, parseOrgHeadline(1, "Projects", keywords))
+	require.Equal(t, orgHeadline{Stars: 1, Title: "WAIT for it"}, parseOrgHeadline(1, "WAIT for it", keywords), "Undeclared keywords are part of the title")
+	require.Equal(t, orgHeadline{Stars: 3, Title: "Time 10:30 in :notes"}, parseOrgHeadline(3, "Time 10:30 in :notes", keywords))
+}
+
+func TestImportOrg(t *testing.T) {
+	input := `#+TITLE: Tasks
+#+TODO: TODO NEXT | DONE CANCELED
+
+Some introduction that is dropped.
+
+- [ ] Read the manual
+
+* Work
+** NEXT [#A] Fix the build :ci:
+DEADLINE: <2025-08-15 Fri +1w> SCHEDULED: <2025-08-10 Sun>
+:PROPERTIES:
+:ID: build
+:CREATED: [2025-08-01 Fri]
+:END:
+Notes about the build.
+- [X] Update the runner
+  - [ ] Check the cache
+- [ ] Rerun the tests
+*** TODO Ask @bob
+** CANCELED Old idea
+CLOSED: [2025-08-05 Tue 10:00]
+:LOGBOOK:
+- State "CANCELED" from "TODO" [2025-08-05 Tue 10:00]
+:END:
+*** Details
+**** TODO Deep task
+* Home
+- [ ] Water plants
+`
+	items, err := importOrg(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Read the manual",
+		"Work", "Fix the build #ci", "Update the runner", "Check the cache", "Rerun the tests", "Ask @bob", "Old idea",
+		"Details", "Deep task",
+		"Home", "Water plants",
+	}, contents(items))
+
+	levels := make([]int, len(items))
+	for i, item := range items {
+		levels[i] = item.Level
+	}
+	require.Equal(t, []int{0, 1, 0, 2, 4, 2, 2, 0, 3, 0, 1, 0}, levels)
+	require.Equal(t, TypeSection, items[8].Type)
+
+	require.False(t, *items[2].Checked)
+	require.Equal(t, map[string]string{
+		"priority":  "high",
+		"due":       "2025-08-15",
+		"recur":     "every-1w",
+		"scheduled": "2025-08-10",
+		"id":        "build",
+		"created":   "2025-08-01",
+	}, items[2].Metadata)
+	require.Equal(t, []string{"#ci"}, items[2].Tags)
+
+	require.True(t, *items[3].Checked)
+	require.Nil(t, items[3].Metadata)
+	require.Equal(t, []string{"@bob"}, items[6].Tags)
+
+	require.True(t, *items[7].Checked, "Done keywords complete tasks")
+	require.Equal(t, map[string]string{"completed": "2025-08-05"}, items[7].Metadata)
+}
+
+func TestImportOrg_MetadataInTitle(t *testing.T) {
+	input := `* TODO Refactor key:value parser
+* TODO Ship due:2025-09-01
+DEADLINE: <2025-08-20 Wed>
+- [ ] Check owner:bob
+`
+	items, err := importOrg(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Refactor parser", "Ship", "Check"}, contents(items))
+	require.Equal(t, map[string]string{"key": "value"}, items[0].Metadata)
+	require.Equal(t, map[string]string{"due": "2025-08-20"}, items[1].Metadata, "Planning lines take precedence over the title")
+	require.Equal(t, map[string]string{"owner": "bob"}, items[2].Metadata)
+}
+
+func TestOrgPriority(t *testing.T) {
+	for value, expected := range map[string]string{"highest": "A", "high": "A", "medium": "B", "low": "C", "lowest": "C", "D": "D", "b": "B", "1": "A"} {
+		priority, ok := orgPriority(value)
+		require.True(t, ok, value)
+		require.Equal(t, expected, priority, value)
+	}
+	_, ok := orgPriority("soon")
+	require.False(t, ok)
+}
+
+func TestExportOrg(t *testing.T) {
+	content := `- [ ] Read the manual
+
+# Work
+
+## Backend
+
+- [ ] Fix the build #ci priority:high due:2025-08-15 recur:weekly id:build
+  - [x] Update the runner completed:2025-08-12
+    - [ ] Check the cache
+  - [ ] Rerun the tests note:"Use the new flags"
+- [x] Deploy completed:2025-08-14 scheduled:2025-08-13 recur:monthly
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportOrg(&buf, items))
+	require.Equal(t, `* TODO Read the manual
+* Work
+** Backend
+*** TODO [#A] Fix the build :ci:
+DEADLINE: <2025-08-15 Fri +1w>
+:PROPERTIES:
+:ID: build
+:END:
+**** DONE Update the runner
+CLOSED: [2025-08-12 Tue]
+***** TODO Check the cache
+**** TODO Rerun the tests
+:PROPERTIES:
+:NOTE: Use the new flags
+:END:
+*** DONE Deploy
+CLOSED: [2025-08-14 Thu] SCHEDULED: <2025-08-13 Wed>
+:PROPERTIES:
+:RECUR: monthly
+:END:
+`, buf.String())
+}
+
+func TestExportOrg_WeekdayRecur(t *testing.T) {
+	items, err := parseMarkdownFile(createTestFile(t, "- [ ] Stand-up due:2025-08-14 recur:\"every weekday\"\n"))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportOrg(&buf, items))
+	require.Equal(t, `* TODO Stand-up
+DEADLINE: <2025-08-14 Thu>
+:PROPERTIES:
+:RECUR: every weekday
+:END:
+`, buf.String(), "Org repeaters can't give weekdays, so the rule stays a property")
+}
+
+func TestOrg_RoundTrip(t *testing.T) {
+	content := `- [ ] Read the manual
+
+# Work
+
+## Backend
+
+- [ ] Fix the build #ci due:2025-08-15 id:build priority:high recur:every-1w
+  - [x] Update the runner completed:2025-08-12
+    - [ ] Check the cache
+- [x] Deploy scheduled:2025-08-13
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportOrg(&buf, items))
+
+	imported, err := importOrg(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+
+	path := createTestFile(t, "")
+	tm, err := NewTaskManager(path)
+	require.NoError(t, err)
+	tm.Items = imported
+	require.NoError(t, tm.Save())
+	require.Equal(t, content, readTestFile(t, path))
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ItemRecord is the structured form of an item printed by ls and search
+type ItemRecord struct {
+	ID          int               `json:"id"`
+	Type        string            `json:"type"`             // "section" or "task"
+	Level       int               `json:"level"`            // Heading level for sections, indentation for tasks
+	Status      string            `json:"status,omitempty"` // "open" or "done" for tasks
+	Description string            `json:"description"`
+	Section     []string          `json:"section"` // Names of the sections the item is nested in
+	Line        int               `json:"line"`    // Line number in the file, 0 if unknown
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// newItemRecord builds the record of the item at index, path being its section path
+func newItemRecord(item Item, index int, path []string) ItemRecord {
+	record := ItemRecord{
+		ID:          index + 1,
+		Level:       item.Level,
+		Description: item.Content,
+		Section:     path,
+		Line:        item.LineNumber,
+		Metadata:    item.Metadata,
+	}
+
+	switch item.Type {
+	case TypeSection:
+		record.Type = "section"
+		record.Section = path[:len(path)-1] // A section's path ends with its own name
+	case TypeTask:
+		record.Type = "task"
+		record.Status = "open"
+		if item.Checked != nil && *item.Checked {
+			record.Status = "done"
+		}
+	}
+
+	if record.Section == nil {
+		record.Section = []string{}
+	}
+	if len(record.Metadata) == 0 {
+		record.Metadata = nil
+	}
+	return record
+}
+
+// recordFields returns the columns of a record for tabular formats
+func recordFields(record ItemRecord) []string {
+	var metadata []string
+	for _, key := range slices.Sorted(maps.Keys(record.Metadata)) {
+		metadata = append(metadata, key+":"+formatMetadataValue(record.Metadata[key]))
+	}
+
+	return []string{
+		strconv.Itoa(record.ID),
+		record.Type,
+		strconv.Itoa(record.Level),
+		record.Status,
+		record.Description,
+		strings.Join(record.Section, " / "),
+		strconv.Itoa(record.Line),
+		strings.Join(metadata, " "),
+	}
+}
+
+// recordColumns names the columns of tabular formats
+var recordColumns = []string{"id", "type", "level", "status", "description", "section", "line", "metadata"}
+
+// cleanField replaces the tabs and newlines that can't be escaped in tab-separated output by spaces
+var cleanField = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+// outputFormats lists the structured formats of ls and search
+var outputFormats = map[string]func(w io.Writer, records []ItemRecord) error{
+	"json": func(w io.Writer, records []ItemRecord) error {
+		if records == nil {
+			records = []ItemRecord{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	},
+	"jsonl": func(w io.Writer, records []ItemRecord) error {
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"tsv": func(w io.Writer, records []ItemRecord) error {
+		if _, err := fmt.Fprintln(w, strings.Join(recordColumns, "\t")); err != nil {
+			return err
+		}
+		for _, record := range records {
+			fields := recordFields(record)
+			for i, field := range fields {
+				fields[i] = cleanField.Replace(field)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"csv": func(w io.Writer, records []ItemRecord) error {
+		cw := csv.NewWriter(w)
+		cw.Write(recordColumns)
+		for _, record := range records {
+			cw.Write(recordFields(record))
+		}
+		cw.Flush()
+		return cw.Error()
+	},
+}
+
+// lookupOutputFormat returns the writer of a structured format, or nil for the default human-readable output
+func lookupOutputFormat(name string) (func(w io.Writer, records []ItemRecord) error, error) {
+	if name == "" || name == "text" {
+		return nil, nil
+	}
+	write, ok := outputFormats[name]
+	if !ok {
+		return nil, errorf(ExitUsage, "unsupported output format '%s' (must be text, %s)", name, strings.Join(slices.Sorted(maps.Keys(outputFormats)), ", "))
+	}
+	return write, nil
+}
+
+// addOutputFormatFlag adds the --format flag selecting the output format of a listing command
+func addOutputFormatFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVar(format, "format", "text", "Output format (text, json, jsonl, tsv, csv)")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append([]string{"text"}, slices.Sorted(maps.Keys(outputFormats))...), cobra.ShellCompDirectiveNoFileComp
+	})
+}
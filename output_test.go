@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const outputTestContent = `# Work
+- [ ] Ship release due:2025-08-13 note:"two words"
+  - [x] Tag build
+## Meetings
+- [ ] Standup
+`
+
+func outputTestRecords(t *testing.T) []ItemRecord {
+	t.Helper()
+
+	filename := createTestFile(t, outputTestContent)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	paths := sectionPaths(items)
+	records := make([]ItemRecord, len(items))
+	for i, item := range items {
+		records[i] = newItemRecord(item, i, paths[i])
+	}
+	return records
+}
+
+func TestNewItemRecord(t *testing.T) {
+	records := outputTestRecords(t)
+	require.Len(t, records, 5)
+
+	require.Equal(t, ItemRecord{ID: 1, Type: "section", Level: 1, Description: "Work", Section: []string{}, Line: 1}, records[0])
+	require.Equal(t, ItemRecord{
+		ID: 2, Type: "task", Level: 0, Status: "open", Description: "Ship release",
+		Section: []string{"Work"}, Line: 2,
+		Metadata: map[string]string{"due": "2025-08-13", "note": "two words"},
+	}, records[1])
+	require.Equal(t, "done", records[2].Status)
+	require.Equal(t, 2, records[2].Level)
+	require.Equal(t, []string{"Work"}, records[3].Section, "A section's path doesn't include itself")
+	require.Equal(t, []string{"Work", "Meetings"}, records[4].Section)
+}
+
+func TestOutputFormats(t *testing.T) {
+	records := outputTestRecords(t)[:3]
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"tsv", "id\ttype\tlevel\tstatus\tdescription\tsection\tline\tmetadata\n" +
+			"1\tsection\t1\t\tWork\t\t1\t\n" +
+			"2\ttask\t0\topen\tShip release\tWork\t2\tdue:2025-08-13 note:\"two words\"\n" +
+			"3\ttask\t2\tdone\tTag build\tWork\t3\t\n"},
+		{"csv", "id,type,level,status,description,section,line,metadata\n" +
+			"1,section,1,,Work,,1,\n" +
+			"2,task,0,open,Ship release,Work,2,\"due:2025-08-13 note:\"\"two words\"\"\"\n" +
+			"3,task,2,done,Tag build,Work,3,\n"},
+		{"jsonl", `{"id":1,"type":"section","level":1,"description":"Work","section":[],"line":1}` + "\n" +
+			`{"id":2,"type":"task","level":0,"status":"open","description":"Ship release","section":["Work"],"line":2,"metadata":{"due":"2025-08-13","note":"two words"}}` + "\n" +
+			`{"id":3,"type":"task","level":2,"status":"done","description":"Tag build","section":["Work"],"line":3}` + "\n"},
+	}
+
+	for _, tc := range testCases {
+		write, err := lookupOutputFormat(tc.format)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, write(&buf, records))
+		require.Equal(t, tc.expected, buf.String(), "Format: %s", tc.format)
+	}
+}
+
+func TestOutputFormats_JSON(t *testing.T) {
+	write, err := lookupOutputFormat("json")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, write(&buf, outputTestRecords(t)))
+
+	var decoded []ItemRecord
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, outputTestRecords(t), decoded)
+
+	buf.Reset()
+	require.NoError(t, write(&buf, nil))
+	require.Equal(t, "[]\n", buf.String(), "No items is an empty array")
+}
+
+func TestLookupOutputFormat(t *testing.T) {
+	for _, name := range []string{"", "text"} {
+		write, err := lookupOutputFormat(name)
+		require.NoError(t, err)
+		require.Nil(t, write, "Format: %s", name)
+	}
+
+	_, err := lookupOutputFormat("xml")
+	require.Error(t, err)
+}
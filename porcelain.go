@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The porcelain output is meant for scripts and is kept stable across releases.
+// Every line is a record: its type followed by tab-separated fields, without colors or headers.
+// Tabs and newlines in fields are replaced by spaces. Commands print nothing when there is nothing to report.
+// Records may gain fields at the end; other changes get a new version, selected with --porcelain=<version>.
+
+// porcelainVersions lists the supported versions of the porcelain format, the first one being the default
+var porcelainVersions = []string{"v1"}
+
+// porcelain is the porcelain version selected with --porcelain, empty for human-readable output
+var porcelain string
+
+// checkPorcelainVersion validates the version selected with --porcelain
+func checkPorcelainVersion() error {
+	if porcelain != "" && !slices.Contains(porcelainVersions, porcelain) {
+		return errorf(ExitUsage, "unsupported porcelain version '%s' (must be %s)", porcelain, strings.Join(porcelainVersions, ", "))
+	}
+	return nil
+}
+
+// porcelainLine formats a porcelain record
+func porcelainLine(record string, fields ...any) string {
+	parts := []string{record}
+	for _, field := range fields {
+		parts = append(parts, cleanField.Replace(fmt.Sprint(field)))
+	}
+	return strings.Join(parts, "\t")
+}
+
+// printPorcelain prints a porcelain record
+func printPorcelain(record string, fields ...any) {
+	fmt.Println(porcelainLine(record, fields...))
+}
+
+// itemFields returns the fields describing an item in porcelain records,
+// the same columns as the tsv output format
+func itemFields(item Item, index int, path []string) []any {
+	var fields []any
+	for _, field := range recordFields(newItemRecord(item, index, path)) {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// printPorcelainItem prints the item record of the item at index
+func printPorcelainItem(items []Item, index int, paths [][]string) {
+	printPorcelain("item", itemFields(items[index], index, paths[index])...)
+}
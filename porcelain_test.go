@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorcelainLine(t *testing.T) {
+	require.Equal(t, "done\t3", porcelainLine("done", 3))
+	require.Equal(t, "stopped\tWrite the docs\t90", porcelainLine("stopped", "Write\tthe\ndocs", 90))
+}
+
+func TestItemFields(t *testing.T) {
+	content := `# Project
+- [ ] Write tests #work due:2025-08-15
+- [x] Ship
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+	paths := sectionPaths(items)
+
+	require.Equal(t, "item\t1\tsection\t1\t\tProject\t\t1\t", porcelainLine("item", itemFields(items[0], 0, paths[0])...))
+	require.Equal(t, "item\t2\ttask\t0\topen\tWrite tests #work\tProject\t2\tdue:2025-08-15", porcelainLine("item", itemFields(items[1], 1, paths[1])...))
+	require.Equal(t, "item\t3\ttask\t0\tdone\tShip\tProject\t3\t", porcelainLine("item", itemFields(items[2], 2, paths[2])...))
+}
+
+func TestCheckPorcelainVersion(t *testing.T) {
+	t.Cleanup(func() { porcelain = "" })
+
+	porcelain = ""
+	require.NoError(t, checkPorcelainVersion())
+
+	porcelain = "v1"
+	require.NoError(t, checkPorcelainVersion())
+	require.False(t, shouldUseColor(), "Porcelain output has no colors")
+
+	porcelain = "v2"
+	err := checkPorcelainVersion()
+	require.EqualError(t, err, "unsupported porcelain version 'v2' (must be v1)")
+	require.Equal(t, ExitUsage, exitCode(err))
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// priorityShorthands maps the "!" markers accepted by add to priority values
+var priorityShorthands = map[string]string{
+	"!":   "low",
+	"!!":  "medium",
+	"!!!": "high",
+}
+
+// metadataValue returns the metadata value of a task, with "p" accepted as an alias of "priority"
+func metadataValue(item Item, key string) (string, bool) {
+	value, ok := item.Metadata[key]
+	if !ok && key == "priority" {
+		value, ok = item.Metadata["p"]
+	}
+	return value, ok
+}
+
+// taskPriority returns the priority rank of a task (lower is more important), if it has a valid priority
+func taskPriority(item Item) (int, bool) {
+	value, ok := metadataValue(item, "priority")
+	if !ok {
+		return 0, false
+	}
+	return priorityRank(value)
+}
+
+// extractPriorityShorthand removes a standalone "!", "!!" or "!!!" from a task description
+// and returns the remaining description with the priority it stands for
+func extractPriorityShorthand(description string) (string, string) {
+	var priority string
+	var words []string
+	for _, word := range strings.Fields(description) {
+		if value, ok := priorityShorthands[word]; ok && priority == "" {
+			priority = value
+			continue
+		}
+		words = append(words, word)
+	}
+	if priority == "" {
+		return description, ""
+	}
+	return strings.Join(words, " "), priority
+}
+
+// normalizePriority turns the "p" alias into "priority" in new task metadata
+func normalizePriority(metadata map[string]string) {
+	if value, ok := metadata["p"]; ok {
+		if _, exists := metadata["priority"]; !exists {
+			metadata["priority"] = value
+		}
+		delete(metadata, "p")
+	}
+}
+
+// priorityColor returns the color used for the description of an open task with the given priority rank
+func priorityColor(rank int) string {
+	switch {
+	case rank <= 1:
+		return "\033[1;91m" // Bold bright red for highest and high
+	case rank == 2:
+		return "\033[93m" // Bright yellow for medium
+	default:
+		return "\033[94m" // Bright blue for low and below
+	}
+}
+
+// parseMinPriority parses the --min-priority flag into a rank
+func parseMinPriority(value string) (int, error) {
+	rank, ok := priorityRank(value)
+	if !ok {
+		return 0, errorf(ExitParse, "invalid priority '%s'", value)
+	}
+	return rank, nil
+}
+
+// belowPriority reports, for every item, whether it is a task less important than the rank.
+// Tasks without a priority are below any rank.
+func belowPriority(items []Item, rank int) []bool {
+	below := make([]bool, len(items))
+	for i, item := range items {
+		if item.Type != TypeTask {
+			continue
+		}
+		r, ok := taskPriority(item)
+		below[i] = !ok || r > rank
+	}
+	return below
+}
+
+// hideEmptySections additionally hides the sections whose tasks are all hidden
+func hideEmptySections(items []Item, hidden []bool) {
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Type != TypeSection {
+			continue
+		}
+		empty := true
+		for j := i + 1; j < sectionEnd(items, i); j++ {
+			if !hidden[j] && items[j].Type == TypeTask {
+				empty = false
+				break
+			}
+		}
+		hidden[i] = empty
+	}
+}
+
+// nextTask returns the index of the most important open task that is neither blocked nor snoozed, or -1.
+// Tasks are ranked by priority then due date, ties keeping file order.
+func nextTask(items []Item, dates DateContext) int {
+	hidden := hiddenItems(items, dates)
+	var candidates []int
+	for i, item := range items {
+		if item.Type == TypeTask && (item.Checked == nil || !*item.Checked) && len(item.BlockedBy) == 0 && !hidden[i] {
+			candidates = append(candidates, i)
+		}
+	}
+
+	tasks := make([]Item, len(candidates))
+	for i, index := range candidates {
+		tasks[i] = items[index]
+	}
+	compare := taskComparator(tasks, []string{"priority", "due"})
+
+	best := -1
+	for _, i := range candidates {
+		if best < 0 || compare(items[i], items[best]) < 0 {
+			best = i
+		}
+	}
+	return best
+}
+
+func newNextCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "next",
+		Short: "Show the task to do now",
+		Long: `Show the most important open task that is neither blocked nor snoozed.
+Tasks are ranked by priority (priority: or p: metadata), then due date, then position in the file.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadFileConfig(filePath)
+			if err != nil {
+				return err
+			}
+			items, err := parseMarkdownFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			index := nextTask(items, newDateContext(cfg))
+			if index < 0 {
+				if porcelain == "" {
+					fmt.Fprintln(os.Stderr, "No open tasks")
+				}
+				return nil
+			}
+
+			if porcelain != "" {
+				printPorcelainItem(items, index, sectionPaths(items))
+				return nil
+			}
+
+			fmt.Println(formatItem(items[index], index))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskPriority(t *testing.T) {
+	task := func(metadata map[string]string) Item {
+		return Item{Type: TypeTask, Metadata: metadata}
+	}
+
+	rank, ok := taskPriority(task(map[string]string{"priority": "high"}))
+	require.True(t, ok)
+	require.Equal(t, 1, rank)
+
+	rank, ok = taskPriority(task(map[string]string{"p": "A"}))
+	require.True(t, ok, "p is an alias of priority")
+	require.Equal(t, 0, rank)
+
+	rank, ok = taskPriority(task(map[string]string{"priority": "low", "p": "A"}))
+	require.True(t, ok)
+	require.Equal(t, 3, rank, "priority wins over p")
+
+	_, ok = taskPriority(task(map[string]string{"priority": "someday"}))
+	require.False(t, ok)
+
+	_, ok = taskPriority(task(nil))
+	require.False(t, ok)
+}
+
+func TestExtractPriorityShorthand(t *testing.T) {
+	testCases := []struct {
+		input       string
+		description string
+		priority    string
+	}{
+		{"Ship release !!!", "Ship release", "high"},
+		{"!! Fix bug", "Fix bug", "medium"},
+		{"Water plants !", "Water plants", "low"},
+		{"Say hello!", "Say hello!", ""},
+		{"Too loud !!!!", "Too loud !!!!", ""},
+		{"No priority", "No priority", ""},
+	}
+
+	for _, tc := range testCases {
+		description, priority := extractPriorityShorthand(tc.input)
+		require.Equal(t, tc.description, description, "Input: %s", tc.input)
+		require.Equal(t, tc.priority, priority, "Input: %s", tc.input)
+	}
+}
+
+func TestNormalizePriority(t *testing.T) {
+	metadata := map[string]string{"p": "high", "due": "2025-08-15"}
+	normalizePriority(metadata)
+	require.Equal(t, map[string]string{"priority": "high", "due": "2025-08-15"}, metadata)
+
+	metadata = map[string]string{"p": "high", "priority": "low"}
+	normalizePriority(metadata)
+	require.Equal(t, map[string]string{"priority": "low"}, metadata)
+}
+
+func TestBelowPriority(t *testing.T) {
+	content := `# Work
+- [ ] Urgent priority:highest
+- [ ] Later p:low
+- [ ] Unprioritized
+- [ ] Medium priority:medium
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	rank, err := parseMinPriority("medium")
+	require.NoError(t, err)
+
+	hidden := belowPriority(items, rank)
+	require.Equal(t, []bool{false, false, true, true, false}, hidden)
+
+	_, err = parseMinPriority("someday")
+	require.Error(t, err)
+}
+
+func TestHideEmptySections(t *testing.T) {
+	content := `# Work
+- [ ] Visible
+# Home
+- [ ] Hidden
+## Garden
+- [ ] Visible
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	hidden := []bool{false, false, false, true, false, false}
+	hideEmptySections(items, hidden)
+	require.Equal(t, []bool{false, false, false, true, false, false}, hidden, "Home has a visible task in a subsection")
+
+	hidden = []bool{false, true, false, true, false, true}
+	hideEmptySections(items, hidden)
+	require.Equal(t, []bool{true, true, true, true, true, true}, hidden)
+}
+
+func TestNextTask(t *testing.T) {
+	content := `# Work
+- [ ] Unprioritized
+- [x] Done priority:highest
+- [ ] Blocked priority:highest after:setup
+- [ ] Snoozed priority:highest snooze:2025-09-01
+- [ ] Medium later priority:medium due:2025-08-20
+- [ ] Medium sooner priority:medium due:2025-08-14
+- [ ] Setup id:setup p:low
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	require.Equal(t, 6, nextTask(items, dates))
+
+	items[5].Checked = func() *bool { b := true; return &b }()
+	items[6].Checked = func() *bool { b := true; return &b }()
+	require.Equal(t, 7, nextTask(items, dates))
+
+	items[7].Checked = func() *bool { b := true; return &b }()
+	require.Equal(t, 1, nextTask(items, dates), "Blocked status is resolved when the file is loaded")
+
+	require.Equal(t, -1, nextTask(nil, dates))
+}
+
+func TestSortByPriorityAlias(t *testing.T) {
+	a := Item{Type: TypeTask, Content: "a", Metadata: map[string]string{"priority": "low"}}
+	b := Item{Type: TypeTask, Content: "b", Metadata: map[string]string{"p": "high"}}
+	require.Positive(t, compareTasks(a, b, []string{"priority"}))
+}
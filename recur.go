@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurRule describes how often a recurring task repeats
+type RecurRule struct {
+	Every    int            // Number of units between occurrences
+	Unit     byte           // 'd', 'w', 'm' or 'y'
+	Weekdays []time.Weekday // Days of the week weekly rules fall on, Sunday first; any day of the due date's weekday if empty
+	WhenDone bool           // Occurrences count from the completion date rather than the due date
+}
+
+// recurUnits maps the unit words of rules such as "every 2 weeks" to units
+var recurUnits = map[string]byte{
+	"day": 'd', "days": 'd',
+	"week": 'w', "weeks": 'w',
+	"month": 'm', "months": 'm',
+	"year": 'y', "years": 'y',
+}
+
+// recurWeekdays maps the day names of rules such as "every week on Monday, Friday" to weekdays
+var recurWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// parseRecurRule parses a recur metadata value: daily, weekly, monthly, yearly,
+// or every-<n><unit> such as every-2w or every-3d (every-day, every-week... are accepted too).
+// The Obsidian Tasks forms are accepted as well: "every 2 weeks", "every weekday",
+// "every week on Monday, Wednesday and Friday", any of them followed by "when done".
+func parseRecurRule(original string) (RecurRule, error) {
+	value := strings.ToLower(strings.TrimSpace(original))
+	invalid := errorf(ExitParse, "invalid recur rule '%s'", original)
+
+	value, whenDone := strings.CutSuffix(value, " when done")
+
+	var weekdays []time.Weekday
+	if interval, days, ok := strings.Cut(value, " on "); ok {
+		if weekdays, ok = parseRecurWeekdays(days); !ok {
+			return RecurRule{}, invalid
+		}
+		value = interval
+	}
+	value = strings.Join(strings.Fields(value), "-")
+
+	rule, ok := parseRecurInterval(value)
+	if value == "every-weekday" && weekdays == nil {
+		rule, ok = RecurRule{Every: 1, Unit: 'w'}, true
+		weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	if !ok || (weekdays != nil && rule.Unit != 'w') {
+		return RecurRule{}, invalid
+	}
+
+	rule.Weekdays = weekdays
+	rule.WhenDone = whenDone
+	return rule, nil
+}
+
+// parseRecurInterval parses the interval of a rule with its words joined by dashes, e.g. weekly, every-2w or every-2-weeks
+func parseRecurInterval(value string) (RecurRule, bool) {
+	switch value {
+	case "daily", "every-day":
+		return RecurRule{Every: 1, Unit: 'd'}, true
+	case "weekly", "every-week":
+		return RecurRule{Every: 1, Unit: 'w'}, true
+	case "monthly", "every-month":
+		return RecurRule{Every: 1, Unit: 'm'}, true
+	case "yearly", "every-year":
+		return RecurRule{Every: 1, Unit: 'y'}, true
+	}
+
+	spec, ok := strings.CutPrefix(value, "every-")
+	if !ok || len(spec) < 2 {
+		return RecurRule{}, false
+	}
+
+	if count, word, ok := strings.Cut(spec, "-"); ok {
+		n, err := strconv.Atoi(count)
+		unit, known := recurUnits[word]
+		if err != nil || n < 1 || !known {
+			return RecurRule{}, false
+		}
+		return RecurRule{Every: n, Unit: unit}, true
+	}
+
+	n, err := strconv.Atoi(spec[:len(spec)-1])
+	if err != nil || n < 1 {
+		return RecurRule{}, false
+	}
+
+	unit := spec[len(spec)-1]
+	if !strings.ContainsRune("dwmy", rune(unit)) {
+		return RecurRule{}, false
+	}
+
+	return RecurRule{Every: n, Unit: unit}, true
+}
+
+// parseRecurWeekdays parses a list of day names such as "monday, wednesday and friday", sorted from Sunday
+func parseRecurWeekdays(list string) ([]time.Weekday, bool) {
+	var weekdays []time.Weekday
+	for _, name := range strings.Fields(strings.ReplaceAll(list, ",", " ")) {
+		if name == "and" {
+			continue
+		}
+		weekday, ok := recurWeekdays[name]
+		if !ok {
+			return nil, false
+		}
+		if !slices.Contains(weekdays, weekday) {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	slices.Sort(weekdays)
+	return weekdays, len(weekdays) > 0
+}
+
+// addMonths adds months to t, keeping its day but clamping it to the last day of shorter months
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	last := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return time.Date(year, month+time.Month(months), min(day, last), hour, minute, sec, t.Nanosecond(), t.Location())
+}
+
+// advance returns the date n occurrences after t.
+// Months and years count from the day of t, so that the 31st recurs on the last day of shorter months.
+func (r RecurRule) advance(t time.Time, n int) time.Time {
+	switch r.Unit {
+	case 'd':
+		return t.AddDate(0, 0, n*r.Every)
+	case 'w':
+		return t.AddDate(0, 0, 7*n*r.Every)
+	case 'm':
+		return addMonths(t, n*r.Every)
+	default:
+		return addMonths(t, 12*n*r.Every)
+	}
+}
+
+// Next returns the first occurrence after today, counting from the current due date
+// (or from today when the task has none or the rule counts from completion)
+func (r RecurRule) Next(due time.Time, hasDue bool, today time.Time) time.Time {
+	today = startOfDay(today)
+	if !hasDue || r.WhenDone {
+		due = today
+	}
+	if len(r.Weekdays) > 0 {
+		return r.nextWeekday(due, today)
+	}
+
+	n := 1
+	for !r.advance(due, n).After(today) {
+		n++
+	}
+	return r.advance(due, n)
+}
+
+// nextWeekday returns the first of the rule's weekdays after both the due date and today
+// that falls in a week a multiple of Every weeks after the week of the due date, weeks starting on Monday
+func (r RecurRule) nextWeekday(due time.Time, today time.Time) time.Time {
+	monday := due.AddDate(0, 0, -int((due.Weekday()+6)%7))
+	day := due
+	if today.After(day) {
+		day = today
+	}
+	for {
+		day = day.AddDate(0, 0, 1)
+		weeks := int(day.Sub(monday).Round(24*time.Hour).Hours()/24) / 7
+		if weeks%r.Every == 0 && slices.Contains(r.Weekdays, day.Weekday()) {
+			return day
+		}
+	}
+}
+
+// checkRecurRule returns an error if the recur metadata, when present, is not a valid rule
+func checkRecurRule(metadata map[string]string) error {
+	if value, ok := metadata["recur"]; ok {
+		if _, err := parseRecurRule(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskBlockEnd returns the index just past the subtasks of the task at index
+func taskBlockEnd(items []Item, index int) int {
+	end := index + 1
+	for end < len(items) && items[end].Type == TypeTask && items[end].Level > items[index].Level {
+		end++
+	}
+	return end
+}
+
+// Recur adds the next occurrence of the recurring task at index right after it and its subtasks.
+// The new occurrence and its subtasks are open and get the next due date; the completed task is kept as a record
+// and loses its recur rule, so that completing it again after an undo doesn't add another occurrence.
+// Stable IDs move to the copies as well, so that tasks waiting on a recurring task wait on its next occurrence.
+// Completion dates are dropped from the copies and creation dates are reset to today.
+// It returns the index of the new occurrence, or -1 if the task does not recur.
+func (tm *TaskManager) Recur(index int, dates DateContext) (int, error) {
+	item, err := tm.GetItem(index)
+	if err != nil {
+		return -1, err
+	}
+
+	if item.Type != TypeTask {
+		return -1, itemErrorf(ExitWrongType, index+1, "item at index %d is not a task", index)
+	}
+
+	value, ok := item.Metadata["recur"]
+	if !ok {
+		return -1, nil
+	}
+
+	rule, err := parseRecurRule(value)
+	if err != nil {
+		return -1, err
+	}
+
+	due, hasDue := dates.Parse(item.Metadata["due"])
+	next := rule.Next(due, hasDue, dates.Today)
+
+	end := taskBlockEnd(tm.Items, index)
+	occurrence := make([]Item, 0, end-index)
+	for _, task := range tm.Items[index:end] {
+		task.Checked = func() *bool { b := false; return &b }()
+		task.Metadata = maps.Clone(task.Metadata)
+		task.EmojiKeys = slices.Clone(task.EmojiKeys)
+		task.LineNumber = 0 // Will be set to proper value when saved
+		delete(task.Metadata, "completed")
+		if _, ok := task.Metadata["created"]; ok {
+			task.Metadata["created"] = dates.today()
+		}
+		occurrence = append(occurrence, task)
+	}
+	occurrence[0].Metadata["due"] = next.Format(time.DateOnly)
+
+	tm.Items = slices.Insert(tm.Items, end, occurrence...)
+	if err := tm.SetMetadata(index, nil, []string{"recur"}); err != nil {
+		return -1, err
+	}
+	for i := index; i < end; i++ {
+		if err := tm.SetMetadata(i, nil, []string{"id"}); err != nil {
+			return -1, err
+		}
+	}
+	return end, nil
+}
+
+// RecurOrWarn adds the next occurrence of the completed task at index like Recur, but only writes a warning to w
+// when its recur rule is invalid, so that completing the task isn't blocked by a rule tasks can't follow.
+// The rule is kept on the task so it can be fixed.
+func (tm *TaskManager) RecurOrWarn(index int, dates DateContext, w io.Writer) (int, error) {
+	next, err := tm.Recur(index, dates)
+	if err != nil && exitCode(err) == ExitParse {
+		fmt.Fprintf(w, "Warning: %v, next occurrence of task %d skipped\n", err, index+1)
+		return -1, nil
+	}
+	return next, err
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurRule(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected RecurRule
+	}{
+		{"daily", RecurRule{Every: 1, Unit: 'd'}},
+		{"Weekly", RecurRule{Every: 1, Unit: 'w'}},
+		{"monthly", RecurRule{Every: 1, Unit: 'm'}},
+		{"yearly", RecurRule{Every: 1, Unit: 'y'}},
+		{"every-week", RecurRule{Every: 1, Unit: 'w'}},
+		{"every-2w", RecurRule{Every: 2, Unit: 'w'}},
+		{"every-10d", RecurRule{Every: 10, Unit: 'd'}},
+		{"every-3m", RecurRule{Every: 3, Unit: 'm'}},
+		{"every week", RecurRule{Every: 1, Unit: 'w'}},
+		{"every 2 weeks", RecurRule{Every: 2, Unit: 'w'}},
+		{"every 3 days when done", RecurRule{Every: 3, Unit: 'd', WhenDone: true}},
+		{"every weekday", RecurRule{Every: 1, Unit: 'w', Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}},
+		{"every-weekday", RecurRule{Every: 1, Unit: 'w', Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}},
+		{"every week on Sunday", RecurRule{Every: 1, Unit: 'w', Weekdays: []time.Weekday{time.Sunday}}},
+		{"every 2 weeks on Friday, Monday and Friday", RecurRule{Every: 2, Unit: 'w', Weekdays: []time.Weekday{time.Monday, time.Friday}}},
+		{"every week on Tuesday when done", RecurRule{Every: 1, Unit: 'w', Weekdays: []time.Weekday{time.Tuesday}, WhenDone: true}},
+	}
+
+	for _, tc := range testCases {
+		rule, err := parseRecurRule(tc.input)
+		require.NoError(t, err, "Input: %s", tc.input)
+		require.Equal(t, tc.expected, rule, "Input: %s", tc.input)
+	}
+
+	for _, input := range []string{"", "sometimes", "every-", "every-0d", "every-2x", "every-w", "every 2 fortnights", "every month on monday", "every week on someday", "every week on", "every weekday on monday"} {
+		_, err := parseRecurRule(input)
+		require.Error(t, err, "Input: %s", input)
+	}
+}
+
+func TestRecurRule_Next(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.Local) }
+	weekly := RecurRule{Every: 1, Unit: 'w'}
+
+	require.Equal(t, day(8, 20), weekly.Next(day(8, 13), true, wednesday), "Due today")
+	require.Equal(t, day(8, 22), weekly.Next(day(8, 15), true, wednesday), "Completed early")
+	require.Equal(t, day(8, 19), weekly.Next(day(7, 29), true, wednesday), "Overdue tasks skip missed occurrences")
+	require.Equal(t, day(8, 20), weekly.Next(time.Time{}, false, wednesday), "No due date counts from today")
+
+	monthly := RecurRule{Every: 1, Unit: 'm'}
+	require.Equal(t, day(9, 13), monthly.Next(day(8, 13), true, wednesday))
+
+	// Month ends are clamped, counting from the day of the due date
+	require.Equal(t, day(8, 31), monthly.Next(time.Date(2025, 1, 31, 0, 0, 0, 0, time.Local), true, wednesday))
+	require.Equal(t, day(2, 28).AddDate(1, 0, 0), monthly.Next(time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local), true, wednesday))
+	yearly := RecurRule{Every: 1, Unit: 'y'}
+	require.Equal(t, day(2, 28).AddDate(1, 0, 0), yearly.Next(time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local), true, wednesday))
+
+	whenDone := RecurRule{Every: 3, Unit: 'd', WhenDone: true}
+	require.Equal(t, day(8, 16), whenDone.Next(day(8, 1), true, wednesday), "Counts from the completion date")
+}
+
+func TestRecurRule_NextWeekday(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.Local) }
+	rule := func(value string) RecurRule {
+		r, err := parseRecurRule(value)
+		require.NoError(t, err)
+		return r
+	}
+
+	// Wednesday 2025-08-13 is today
+	require.Equal(t, day(8, 14), rule("every weekday").Next(day(8, 13), true, wednesday))
+	require.Equal(t, day(8, 18), rule("every weekday").Next(day(8, 15), true, wednesday), "Friday is followed by Monday")
+	require.Equal(t, day(8, 17), rule("every week on Sunday").Next(day(8, 10), true, wednesday))
+	require.Equal(t, day(8, 15), rule("every week on Monday, Friday").Next(day(8, 11), true, wednesday))
+	require.Equal(t, day(8, 18), rule("every week on Monday, Friday").Next(day(8, 15), true, wednesday))
+	require.Equal(t, day(8, 25), rule("every 2 weeks on Monday").Next(day(8, 11), true, wednesday), "Skips the week in between")
+	require.Equal(t, day(8, 14), rule("every 2 weeks on Thursday").Next(time.Time{}, false, wednesday), "No due date counts from today's week")
+}
+
+func TestCheckRecurRule(t *testing.T) {
+	require.NoError(t, checkRecurRule(map[string]string{"recur": "every-2w"}))
+	require.NoError(t, checkRecurRule(map[string]string{"due": "friday"}))
+
+	err := checkRecurRule(map[string]string{"recur": "fortnightly"})
+	require.EqualError(t, err, "invalid recur rule 'fortnightly'")
+	require.Equal(t, ExitParse, exitCode(err))
+}
+
+func TestTaskManager_Recur(t *testing.T) {
+	content := `# Chores
+- [ ] Weekly review recur:weekly due:2025-08-13
+  - [x] Inbox zero
+  - [ ] Plan week
+- [ ] Other task
+`
+	filename := createTestFile(t, content)
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+
+	require.NoError(t, tm.ToggleTask(1, true))
+	next, err := tm.Recur(1, dates)
+	require.NoError(t, err)
+	require.Equal(t, 4, next)
+
+	require.Equal(t, []string{"Chores", "Weekly review", "Inbox zero", "Plan week", "Weekly review", "Inbox zero", "Plan week", "Other task"}, contents(tm.Items))
+
+	// The completed task is kept as a record, the rule moving to the new occurrence
+	require.True(t, *tm.Items[1].Checked)
+	require.Equal(t, "2025-08-13", tm.Items[1].Metadata["due"])
+	require.NotContains(t, tm.Items[1].Metadata, "recur")
+
+	// The new occurrence and its subtasks are open
+	require.False(t, *tm.Items[4].Checked)
+	require.Equal(t, "2025-08-20", tm.Items[4].Metadata["due"])
+	require.Equal(t, "weekly", tm.Items[4].Metadata["recur"])
+	require.False(t, *tm.Items[5].Checked)
+	require.Equal(t, 2, tm.Items[5].Level)
+
+	// Completing the record again after an undo doesn't add another occurrence
+	require.NoError(t, tm.ToggleTask(1, false))
+	require.NoError(t, tm.ToggleTask(1, true))
+	next, err = tm.Recur(1, dates)
+	require.NoError(t, err)
+	require.Equal(t, -1, next)
+
+	// Tasks without recur metadata are left alone
+	next, err = tm.Recur(7, dates)
+	require.NoError(t, err)
+	require.Equal(t, -1, next)
+	require.Len(t, tm.Items, 8)
+
+	// Sections can't recur
+	_, err = tm.Recur(0, dates)
+	require.Error(t, err)
+}
+
+func TestTaskManager_Recur_Timestamps(t *testing.T) {
+	filename := createTestFile(t, "- [x] Water plants recur:weekly due:2025-08-13 created:2025-08-06 completed:2025-08-13\n")
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+
+	next, err := tm.Recur(0, DateContext{Today: wednesday, WeekStart: time.Monday})
+	require.NoError(t, err)
+
+	require.Equal(t, "2025-08-13", tm.Items[0].Metadata["completed"])
+	require.NotContains(t, tm.Items[next].Metadata, "completed")
+	require.Equal(t, "2025-08-13", tm.Items[next].Metadata["created"])
+}
+
+func TestTaskManager_Recur_StableID(t *testing.T) {
+	filename := createTestFile(t, "- [ ] Build id:build recur:daily\n- [ ] Deploy after:build\n")
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+
+	require.NoError(t, tm.ToggleTask(0, true))
+	next, err := tm.Recur(0, DateContext{Today: wednesday, WeekStart: time.Monday})
+	require.NoError(t, err)
+
+	require.NotContains(t, tm.Items[0].Metadata, "id", "The record gives its ID to the next occurrence")
+	require.Equal(t, "build", tm.Items[next].Metadata["id"])
+
+	graph := buildDependencyGraph(tm.Items)
+	require.Equal(t, []int{next}, graph.Prerequisites[2], "Deploy waits on the next build")
+}
+
+func TestTaskManager_Recur_InvalidRule(t *testing.T) {
+	filename := createTestFile(t, "- [ ] Task recur:sometimes\n")
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+
+	_, err = tm.Recur(0, DateContext{Today: wednesday})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid recur rule")
+}
+
+func TestTaskManager_RecurOrWarn(t *testing.T) {
+	filename := createTestFile(t, "- [x] Task recur:sometimes\n- [x] Plants due:2025-08-13 recur:weekly\n")
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	dates := DateContext{Today: wednesday}
+
+	var warnings bytes.Buffer
+	next, err := tm.RecurOrWarn(0, dates, &warnings)
+	require.NoError(t, err)
+	require.Equal(t, -1, next)
+	require.Contains(t, warnings.String(), "invalid recur rule 'sometimes', next occurrence of task 1 skipped")
+	require.Equal(t, "sometimes", tm.Items[0].Metadata["recur"], "The rule is kept so it can be fixed")
+	require.Len(t, tm.Items, 2)
+
+	warnings.Reset()
+	next, err = tm.RecurOrWarn(1, dates, &warnings)
+	require.NoError(t, err)
+	require.Equal(t, 2, next)
+	require.Empty(t, warnings.String())
+}
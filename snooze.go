@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// showAll makes listings and completions include snoozed tasks
+var showAll bool
+
+// snoozeKeys lists the metadata keys holding the date until which a task is hidden
+var snoozeKeys = []string{"snooze", "wait"}
+
+// SnoozedUntil returns the date until which an open task is hidden, if it is still in the future
+func (dc DateContext) SnoozedUntil(item Item) (time.Time, bool) {
+	if item.Type != TypeTask || (item.Checked != nil && *item.Checked) {
+		return time.Time{}, false
+	}
+
+	for _, key := range snoozeKeys {
+		if until, ok := dc.Parse(item.Metadata[key]); ok && until.After(startOfDay(dc.Today)) {
+			return until, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// hiddenItems reports, for every item, whether it is hidden because it or its parent task is snoozed
+func hiddenItems(items []Item, dates DateContext) []bool {
+	hidden := make([]bool, len(items))
+	if showAll {
+		return hidden
+	}
+
+	for i := 0; i < len(items); i++ {
+		if _, ok := dates.SnoozedUntil(items[i]); ok {
+			end := taskBlockEnd(items, i)
+			for j := i; j < end; j++ {
+				hidden[j] = true
+			}
+			i = end - 1
+		}
+	}
+	return hidden
+}
+
+// parseSnoozeDate parses how long to snooze a task: a duration such as 3d or 2w, or a date
+func parseSnoozeDate(value string, dates DateContext) (time.Time, error) {
+	if len(value) >= 2 && value[0] >= '0' && value[0] <= '9' && strings.ContainsRune("dwm", rune(value[len(value)-1])) {
+		value = "+" + value
+	}
+
+	until, ok := dates.Parse(value)
+	if !ok {
+		return time.Time{}, errorf(ExitParse, "invalid snooze date '%s'", value)
+	}
+	if !until.After(startOfDay(dates.Today)) {
+		return time.Time{}, errorf(ExitUsage, "snooze date %s is not in the future", until.Format(time.DateOnly))
+	}
+	return until, nil
+}
+
+func newSnoozeCommand() *cobra.Command {
+	var clearSnooze bool
+
+	cmd := &cobra.Command{
+		Use:   "snooze <id> [duration|date]",
+		Short: "Hide a task until a date",
+		Long: `Hide a task from listings and completions until a date by setting its snooze metadata.
+The date can be a duration (3d, 2w, 1m) or a date (2025-08-10, friday, next month).
+Tasks with a snooze: or wait: date in the future are shown again when the date arrives,
+or when --all is passed. Use --clear to show the task again right away.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Parse the ID
+			index, err := parseItemID(args[0])
+			if err != nil {
+				return err
+			}
+			id := index + 1 // Keep original ID for display
+
+			tm, err := NewTaskManager(filePath)
+			if err != nil {
+				return err
+			}
+
+			if clearSnooze {
+				if err := tm.SetMetadata(index, nil, snoozeKeys); err != nil {
+					return err
+				}
+			} else {
+				if len(args) < 2 {
+					return errorf(ExitUsage, "snooze duration or date is required")
+				}
+
+				until, err := parseSnoozeDate(args[1], newDateContext(tm.Config))
+				if err != nil {
+					return err
+				}
+
+				set := map[string]string{"snooze": until.Format(time.DateOnly)}
+				if err := tm.SetMetadata(index, set, []string{"wait"}); err != nil {
+					return err
+				}
+			}
+
+			if err := tm.Save(); err != nil {
+				return fmt.Errorf("saving file: %w", err)
+			}
+
+			switch {
+			case porcelain != "" && clearSnooze:
+				printPorcelain("unsnoozed", id)
+			case porcelain != "":
+				printPorcelain("snoozed", id, tm.Items[index].Metadata["snooze"])
+			case clearSnooze:
+				fmt.Printf("Unsnoozed task %d\n", id)
+			default:
+				fmt.Printf("Snoozed task %d until %s\n", id, tm.Items[index].Metadata["snooze"])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clearSnooze, "clear", false, "Remove the snooze date")
+
+	// Add completion for task IDs
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeTaskIDs(toComplete, false) // false = incomplete tasks only
+	}
+
+	return cmd
+}
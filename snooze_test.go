@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateContext_SnoozedUntil(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	task := func(checked bool, metadata map[string]string) Item {
+		return Item{Type: TypeTask, Checked: &checked, Metadata: metadata}
+	}
+
+	until, ok := dates.SnoozedUntil(task(false, map[string]string{"snooze": "2025-08-15"}))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local), until)
+
+	_, ok = dates.SnoozedUntil(task(false, map[string]string{"wait": "2025-08-14"}))
+	require.True(t, ok, "wait works like snooze")
+
+	_, ok = dates.SnoozedUntil(task(false, map[string]string{"snooze": "2025-08-13"}))
+	require.False(t, ok, "Shown again on the snooze date")
+
+	_, ok = dates.SnoozedUntil(task(true, map[string]string{"snooze": "2025-08-15"}))
+	require.False(t, ok, "Completed tasks are never snoozed")
+
+	_, ok = dates.SnoozedUntil(task(false, nil))
+	require.False(t, ok)
+}
+
+func TestHiddenItems(t *testing.T) {
+	content := `# Section
+- [ ] Visible
+- [ ] Snoozed snooze:2025-08-20
+  - [ ] Child
+- [ ] Visible again wait:2025-08-01
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	require.Equal(t, []bool{false, false, true, true, false}, hiddenItems(items, dates))
+
+	oldShowAll := showAll
+	showAll = true
+	t.Cleanup(func() { showAll = oldShowAll })
+
+	require.Equal(t, []bool{false, false, false, false, false}, hiddenItems(items, dates))
+}
+
+func TestParseSnoozeDate(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.Local) }
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"3d", day(8, 16)},
+		{"2w", day(8, 27)},
+		{"1m", day(9, 13)},
+		{"+1d", day(8, 14)},
+		{"friday", day(8, 15)},
+		{"2025-09-01", day(9, 1)},
+	}
+
+	for _, tc := range testCases {
+		until, err := parseSnoozeDate(tc.input, dates)
+		require.NoError(t, err, "Input: %s", tc.input)
+		require.Equal(t, tc.expected, until, "Input: %s", tc.input)
+	}
+
+	_, err := parseSnoozeDate("today", dates)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not in the future")
+	require.Equal(t, ExitUsage, exitCode(err))
+
+	_, err = parseSnoozeDate("later", dates)
+	require.Error(t, err)
+	require.Equal(t, ExitParse, exitCode(err))
+}
+
+func TestCompleteItemIDs_HidesSnoozedTasks(t *testing.T) {
+	content := `- [ ] Visible
+- [ ] Snoozed snooze:2999-01-01
+`
+	oldFilePath := filePath
+	filePath = createTestFile(t, content)
+	t.Cleanup(func() { filePath = oldFilePath })
+
+	completions, _ := completeTaskIDs("", false)
+	require.Equal(t, []string{"1\tVisible"}, completions)
+}
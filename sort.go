@@ -0,0 +1,329 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultSortKeys is used when neither --by nor a per-file default is given
+var defaultSortKeys = []string{"status", "priority", "due", "text"}
+
+// parseSortKeys parses a comma-separated list of sort keys.
+// "status" and "text" sort by completion and description, any other key sorts by that metadata value.
+func parseSortKeys(spec string) ([]string, error) {
+	var keys []string
+	for key := range strings.SplitSeq(spec, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if !isIdentifier(key) {
+			return nil, errorf(ExitParse, "invalid sort key '%s'", key)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errorf(ExitUsage, "no sort keys given")
+	}
+	return keys, nil
+}
+
+// isIdentifier reports whether s only contains characters allowed in metadata keys and unquoted values
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	p := &TaskParser{input: s, len: len(s)}
+	return p.parseIdentifier() == s
+}
+
+// priorityRank converts a priority value to a rank where lower means more important.
+// Letters (A, B, ...), numbers (1, 2, ...) and words (highest, high, medium, low, lowest) are recognized.
+func priorityRank(value string) (int, bool) {
+	switch strings.ToLower(value) {
+	case "highest", "critical", "urgent":
+		return 0, true
+	case "high":
+		return 1, true
+	case "medium", "med", "normal":
+		return 2, true
+	case "low":
+		return 3, true
+	case "lowest":
+		return 4, true
+	}
+
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, true
+	}
+
+	if len(value) == 1 {
+		ch := value[0] | 0x20 // lowercase
+		if ch >= 'a' && ch <= 'z' {
+			return int(ch - 'a'), true
+		}
+	}
+
+	return 0, false
+}
+
+// valueKind is the type metadata values are compared as
+type valueKind int
+
+const (
+	kindText     valueKind = iota // Case-insensitive text
+	kindDate                      // ISO dates
+	kindPriority                  // Priorities, for the priority key, see priorityRank
+	kindNumber                    // Numbers
+)
+
+// metadataKind returns the most specific type all the values of key parse as:
+// dates, then priorities for the priority key, then numbers, and finally text
+func metadataKind(key string, values []string) valueKind {
+	all := func(parses func(value string) bool) bool {
+		return !slices.ContainsFunc(values, func(value string) bool { return !parses(value) })
+	}
+
+	switch {
+	case all(func(value string) bool { _, err := time.Parse(time.DateOnly, value); return err == nil }):
+		return kindDate
+	case key == "priority" && all(func(value string) bool { _, ok := priorityRank(value); return ok }):
+		return kindPriority
+	case all(func(value string) bool { _, err := strconv.ParseFloat(value, 64); return err == nil }):
+		return kindNumber
+	default:
+		return kindText
+	}
+}
+
+// compareValues compares two metadata values as the given type
+func compareValues(kind valueKind, a, b string) int {
+	switch kind {
+	case kindDate:
+		da, _ := time.Parse(time.DateOnly, a)
+		db, _ := time.Parse(time.DateOnly, b)
+		return da.Compare(db)
+	case kindPriority:
+		ra, _ := priorityRank(a)
+		rb, _ := priorityRank(b)
+		return cmp.Compare(ra, rb)
+	case kindNumber:
+		fa, _ := strconv.ParseFloat(a, 64)
+		fb, _ := strconv.ParseFloat(b, 64)
+		return cmp.Compare(fa, fb)
+	default:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+// compareMetadataValues compares two metadata values of key using the most specific type they both parse as
+func compareMetadataValues(key, a, b string) int {
+	return compareValues(metadataKind(key, []string{a, b}), a, b)
+}
+
+// taskComparator returns a function comparing tasks using the sort keys in order.
+// The values of a metadata key are all compared as the most specific type the values of the tasks parse as,
+// so that the order is consistent. Tasks missing a metadata value sort after the ones that have it.
+func taskComparator(tasks []Item, keys []string) func(a, b Item) int {
+	kinds := make(map[string]valueKind)
+	for _, key := range keys {
+		var values []string
+		for _, task := range tasks {
+			if value, ok := metadataValue(task, key); ok {
+				values = append(values, value)
+			}
+		}
+		kinds[key] = metadataKind(key, values)
+	}
+
+	return func(a, b Item) int {
+		for _, key := range keys {
+			var c int
+
+			switch key {
+			case "status":
+				doneA := a.Checked != nil && *a.Checked
+				doneB := b.Checked != nil && *b.Checked
+				switch {
+				case doneA == doneB:
+					c = 0
+				case doneB:
+					c = -1
+				default:
+					c = 1
+				}
+
+			case "text":
+				c = strings.Compare(strings.ToLower(a.Content), strings.ToLower(b.Content))
+
+			default:
+				va, okA := metadataValue(a, key)
+				vb, okB := metadataValue(b, key)
+				switch {
+				case okA && okB:
+					c = compareValues(kinds[key], va, vb)
+				case okA:
+					c = -1
+				case okB:
+					c = 1
+				}
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// compareTasks compares two tasks using the sort keys in order, see taskComparator
+func compareTasks(a, b Item, keys []string) int {
+	return taskComparator([]Item{a, b}, keys)(a, b)
+}
+
+// sectionEnd returns the index just past the last child of the section at index
+func sectionEnd(items []Item, index int) int {
+	end := index + 1
+	for end < len(items) {
+		if items[end].Type == TypeSection && items[end].Level <= items[index].Level {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// sortTaskRun stably sorts a run of consecutive tasks.
+// A task followed by more indented tasks forms a block so subtasks stay with their parent.
+func sortTaskRun(tasks []Item, keys []string) {
+	if len(tasks) < 2 {
+		return
+	}
+
+	baseLevel := tasks[0].Level
+	for _, task := range tasks {
+		baseLevel = min(baseLevel, task.Level)
+	}
+
+	var blocks [][]Item
+	for _, task := range tasks {
+		if task.Level <= baseLevel || len(blocks) == 0 {
+			blocks = append(blocks, nil)
+		}
+		blocks[len(blocks)-1] = append(blocks[len(blocks)-1], task)
+	}
+
+	heads := make([]Item, len(blocks))
+	for i, block := range blocks {
+		heads[i] = block[0]
+	}
+	compare := taskComparator(heads, keys)
+	slices.SortStableFunc(blocks, func(a, b []Item) int {
+		return compare(a[0], b[0])
+	})
+
+	pos := 0
+	for _, block := range blocks {
+		pos += copy(tasks[pos:], block)
+	}
+}
+
+// sortItems sorts the tasks of every section in place, leaving sections where they are.
+// Tasks are only reordered among the tasks directly following the same section header.
+func sortItems(items []Item, keys []string) {
+	start := 0
+	for start < len(items) {
+		if items[start].Type != TypeTask {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(items) && items[end].Type == TypeTask {
+			end++
+		}
+
+		sortTaskRun(items[start:end], keys)
+		start = end
+	}
+}
+
+func newSortCommand() *cobra.Command {
+	var by string
+
+	cmd := &cobra.Command{
+		Use:   "sort [section-id]",
+		Short: "Sort tasks within each section",
+		Long: `Sort tasks within each section by metadata or status.
+Sort keys are given as a comma-separated list: "status" puts open tasks first,
+"text" sorts by description and any other key sorts by that metadata value
+(e.g. priority, due). Subtasks are kept with their parent.
+
+When a section ID is given only that section and its subsections are sorted.
+Without --by the file's default sort (see 'tasks config sort') or
+status,priority,due,text is used.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tm, err := NewTaskManager(filePath)
+			if err != nil {
+				return err
+			}
+
+			keys := tm.Config.Sort
+			if by != "" {
+				if keys, err = parseSortKeys(by); err != nil {
+					return err
+				}
+			}
+			if len(keys) == 0 {
+				keys = defaultSortKeys
+			}
+
+			if len(args) == 0 {
+				sortItems(tm.Items, keys)
+			} else {
+				index, err := parseItemID(args[0])
+				if err != nil {
+					return err
+				}
+
+				item, err := tm.GetItem(index)
+				if err != nil {
+					return err
+				}
+				if item.Type != TypeSection {
+					return itemErrorf(ExitWrongType, index+1, "item %d is not a section", index+1)
+				}
+
+				sortItems(tm.Items[index:sectionEnd(tm.Items, index)], keys)
+			}
+
+			if err := tm.Save(); err != nil {
+				return fmt.Errorf("saving file: %w", err)
+			}
+
+			if porcelain != "" {
+				printPorcelain("sorted", strings.Join(keys, ","))
+				return nil
+			}
+
+			fmt.Printf("Sorted tasks by %s\n", strings.Join(keys, ","))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&by, "by", "", "Comma-separated sort keys (status, text or a metadata key)")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeItemIDs(toComplete, ItemFilter{IncludeSections: true})
+	}
+
+	return cmd
+}
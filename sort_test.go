@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// contents returns the content of every item, in order
+func contents(items []Item) []string {
+	var result []string
+	for _, item := range items {
+		result = append(result, item.Content)
+	}
+	return result
+}
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := parseSortKeys("priority, due,status")
+	require.NoError(t, err)
+	require.Equal(t, []string{"priority", "due", "status"}, keys)
+
+	_, err = parseSortKeys("")
+	require.Error(t, err)
+
+	_, err = parseSortKeys("due date")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid sort key")
+}
+
+func TestPriorityRank(t *testing.T) {
+	testCases := []struct {
+		value string
+		rank  int
+		ok    bool
+	}{
+		{"A", 0, true},
+		{"c", 2, true},
+		{"high", 1, true},
+		{"Low", 3, true},
+		{"2", 2, true},
+		{"whenever", 0, false},
+	}
+
+	for _, tc := range testCases {
+		rank, ok := priorityRank(tc.value)
+		require.Equal(t, tc.ok, ok, "Value: %s", tc.value)
+		require.Equal(t, tc.rank, rank, "Value: %s", tc.value)
+	}
+}
+
+func TestCompareMetadataValues(t *testing.T) {
+	t.Run("dates", func(t *testing.T) {
+		require.Negative(t, compareMetadataValues("due", "2025-08-09", "2025-08-10"))
+		require.Positive(t, compareMetadataValues("due", "2025-12-01", "2025-08-10"))
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		require.Negative(t, compareMetadataValues("estimate", "9", "10"))
+		require.Zero(t, compareMetadataValues("estimate", "1.0", "1"))
+	})
+
+	t.Run("priorities", func(t *testing.T) {
+		require.Negative(t, compareMetadataValues("priority", "high", "low"))
+		require.Negative(t, compareMetadataValues("priority", "A", "B"))
+	})
+
+	t.Run("text", func(t *testing.T) {
+		require.Negative(t, compareMetadataValues("owner", "alice", "Bob"))
+	})
+}
+
+func TestMetadataKind(t *testing.T) {
+	require.Equal(t, kindDate, metadataKind("due", []string{"2025-08-09", "2025-08-10"}))
+	require.Equal(t, kindPriority, metadataKind("priority", []string{"high", "A", "1"}))
+	require.Equal(t, kindNumber, metadataKind("estimate", []string{"9", "1.5"}))
+	require.Equal(t, kindNumber, metadataKind("priority", []string{"9", "1.5"}))
+	require.Equal(t, kindText, metadataKind("due", []string{"2025-08-09", "someday"}))
+	require.Equal(t, kindDate, metadataKind("due", nil))
+}
+
+func TestTaskComparator_MixedValues(t *testing.T) {
+	// Compared pair by pair, 9 < 10 as numbers, 10 < 2025-08-10 and 2025-08-10 < 9 as text
+	task := func(value string) Item {
+		return Item{Type: TypeTask, Content: value, Checked: new(bool), Metadata: map[string]string{"ref": value}}
+	}
+	tasks := []Item{task("9"), task("2025-08-10"), task("10")}
+
+	sortTaskRun(tasks, []string{"ref"})
+	require.Equal(t, []string{"10", "2025-08-10", "9"}, contents(tasks), "Every value is compared as text")
+}
+
+func TestSortItems(t *testing.T) {
+	t.Run("sorts within each section", func(t *testing.T) {
+		items := []Item{
+			{Type: TypeSection, Level: 1, Content: "Work"},
+			{Type: TypeTask, Content: "Later", Checked: func() *bool { b := false; return &b }(), Metadata: map[string]string{"due": "2025-09-01"}},
+			{Type: TypeTask, Content: "No date", Checked: func() *bool { b := false; return &b }()},
+			{Type: TypeTask, Content: "Sooner", Checked: func() *bool { b := false; return &b }(), Metadata: map[string]string{"due": "2025-08-01"}},
+			{Type: TypeSection, Level: 1, Content: "Home"},
+			{Type: TypeTask, Content: "Zebra", Checked: func() *bool { b := false; return &b }()},
+			{Type: TypeTask, Content: "Apple", Checked: func() *bool { b := false; return &b }()},
+		}
+
+		sortItems(items, []string{"due", "text"})
+		require.Equal(t, []string{"Work", "Sooner", "Later", "No date", "Home", "Apple", "Zebra"}, contents(items))
+	})
+
+	t.Run("status puts open tasks first", func(t *testing.T) {
+		items := []Item{
+			{Type: TypeTask, Content: "Done", Checked: func() *bool { b := true; return &b }()},
+			{Type: TypeTask, Content: "Open", Checked: func() *bool { b := false; return &b }()},
+		}
+
+		sortItems(items, []string{"status"})
+		require.Equal(t, []string{"Open", "Done"}, contents(items))
+	})
+
+	t.Run("subtasks stay with their parent", func(t *testing.T) {
+		items := []Item{
+			{Type: TypeTask, Content: "Low", Checked: func() *bool { b := false; return &b }(), Metadata: map[string]string{"priority": "low"}},
+			{Type: TypeTask, Level: 2, Content: "Low child", Checked: func() *bool { b := false; return &b }()},
+			{Type: TypeTask, Content: "High", Checked: func() *bool { b := false; return &b }(), Metadata: map[string]string{"priority": "high"}},
+			{Type: TypeTask, Level: 2, Content: "High child", Checked: func() *bool { b := false; return &b }()},
+		}
+
+		sortItems(items, []string{"priority"})
+		require.Equal(t, []string{"High", "High child", "Low", "Low child"}, contents(items))
+	})
+
+	t.Run("stable for equal keys", func(t *testing.T) {
+		items := []Item{
+			{Type: TypeTask, Content: "First", Checked: func() *bool { b := false; return &b }()},
+			{Type: TypeTask, Content: "Second", Checked: func() *bool { b := false; return &b }()},
+			{Type: TypeTask, Content: "Third", Checked: func() *bool { b := false; return &b }()},
+		}
+
+		sortItems(items, []string{"priority"})
+		require.Equal(t, []string{"First", "Second", "Third"}, contents(items))
+	})
+}
+
+func TestSectionEnd(t *testing.T) {
+	items := []Item{
+		{Type: TypeSection, Level: 1, Content: "A"},
+		{Type: TypeTask, Content: "Task"},
+		{Type: TypeSection, Level: 2, Content: "A.1"},
+		{Type: TypeTask, Content: "Subtask"},
+		{Type: TypeSection, Level: 1, Content: "B"},
+	}
+
+	require.Equal(t, 4, sectionEnd(items, 0))
+	require.Equal(t, 4, sectionEnd(items, 2))
+	require.Equal(t, 5, sectionEnd(items, 4))
+}
+
+func TestSaveToFile_KeepsSubtaskIndentation(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "TODO.md")
+	content := "- [ ] Parent\n  - [ ] Child\n"
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	require.NoError(t, tm.Save())
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+}
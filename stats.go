@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// TaskCounts holds the number of open and completed tasks in a group
+type TaskCounts struct {
+	Total   int     `json:"total"`
+	Open    int     `json:"open"`
+	Done    int     `json:"done"`
+	Percent float64 `json:"percent"` // Percentage of completed tasks
+	Overdue int     `json:"overdue"`
+}
+
+// add counts a task in the group
+func (c *TaskCounts) add(item Item, dates DateContext) {
+	c.Total++
+	if item.Checked != nil && *item.Checked {
+		c.Done++
+	} else {
+		c.Open++
+	}
+	if dates.IsOverdue(item) {
+		c.Overdue++
+	}
+	c.Percent = float64(c.Done) / float64(c.Total) * 100
+}
+
+// SectionStats holds the counts of the tasks directly under a section
+type SectionStats struct {
+	Path []string `json:"path"`
+	TaskCounts
+}
+
+// FileStats holds the statistics of a single markdown file
+type FileStats struct {
+	File     string                           `json:"file"`
+	Counts   TaskCounts                       `json:"counts"`
+	Sections []SectionStats                   `json:"sections"`
+	Metadata map[string]map[string]TaskCounts `json:"metadata"` // Counts per value of each breakdown key
+	Activity ActivityStats                    `json:"activity"`
+}
+
+// ActivityStats holds counts based on the created and completed timestamps of tasks
+type ActivityStats struct {
+	Created   int `json:"created_last_7_days"`
+	Completed int `json:"completed_last_7_days"`
+	Stale     int `json:"stale"` // Open tasks created more than defaultStaleDays ago
+}
+
+// statsBreakdownKeys lists the metadata keys tasks are grouped by
+var statsBreakdownKeys = []string{"priority", "assignee", "tag"}
+
+// breakdownValues returns the values a task is grouped under for a breakdown key.
+// Tags can be given as a comma-separated list in either "tag" or "tags", or inline as #tag;
+// @person mentions count as assignees.
+func breakdownValues(item Item, key string) []string {
+	switch key {
+	case "tag":
+		var tags []string
+		for _, metaKey := range []string{"tag", "tags"} {
+			for tag := range strings.SplitSeq(item.Metadata[metaKey], ",") {
+				if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+		}
+		for _, tag := range item.Tags {
+			if tag, ok := strings.CutPrefix(tag, "#"); ok && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		return tags
+
+	case "assignee":
+		var assignees []string
+		if value, ok := item.Metadata[key]; ok {
+			assignees = append(assignees, value)
+		}
+		for _, tag := range item.Tags {
+			if person, ok := strings.CutPrefix(tag, "@"); ok && !slices.Contains(assignees, person) {
+				assignees = append(assignees, person)
+			}
+		}
+		return assignees
+	}
+
+	if value, ok := metadataValue(item, key); ok {
+		return []string{value}
+	}
+	return nil
+}
+
+// computeStats computes the statistics of the items of a file
+func computeStats(file string, items []Item, dates DateContext) FileStats {
+	stats := FileStats{
+		File:     file,
+		Sections: []SectionStats{},
+		Metadata: make(map[string]map[string]TaskCounts),
+	}
+	for _, key := range statsBreakdownKeys {
+		stats.Metadata[key] = make(map[string]TaskCounts)
+	}
+
+	paths := sectionPaths(items)
+	sectionIndex := make(map[string]int)
+
+	for i, item := range items {
+		if item.Type != TypeTask {
+			continue
+		}
+
+		stats.Counts.add(item, dates)
+
+		if dates.Within(item, "created", 7) {
+			stats.Activity.Created++
+		}
+		if item.Checked != nil && *item.Checked && dates.Within(item, "completed", 7) {
+			stats.Activity.Completed++
+		}
+		if dates.IsStale(item, defaultStaleDays) {
+			stats.Activity.Stale++
+		}
+
+		pathKey := strings.Join(paths[i], "\x00")
+		idx, ok := sectionIndex[pathKey]
+		if !ok {
+			idx = len(stats.Sections)
+			sectionIndex[pathKey] = idx
+			stats.Sections = append(stats.Sections, SectionStats{Path: paths[i]})
+		}
+		stats.Sections[idx].add(item, dates)
+
+		for _, key := range statsBreakdownKeys {
+			for _, value := range breakdownValues(item, key) {
+				counts := stats.Metadata[key][value]
+				counts.add(item, dates)
+				stats.Metadata[key][value] = counts
+			}
+		}
+	}
+
+	return stats
+}
+
+// formatSectionPath joins a section path for display
+func formatSectionPath(path []string) string {
+	if len(path) == 0 {
+		return "(no section)"
+	}
+	return strings.Join(path, " / ")
+}
+
+// writeStatsTable writes the statistics of a file as human-readable tables
+func writeStatsTable(out io.Writer, stats FileStats) error {
+	c := stats.Counts
+	fmt.Fprintf(out, "%s: %d tasks, %d open, %d done (%.0f%%), %d overdue\n",
+		stats.File, c.Total, c.Open, c.Done, c.Percent, c.Overdue)
+	if a := stats.Activity; a != (ActivityStats{}) {
+		fmt.Fprintf(out, "Last 7 days: %d created, %d completed; %d stale (open for more than %d days)\n",
+			a.Created, a.Completed, a.Stale, defaultStaleDays)
+	}
+
+	writeTable := func(title string, rows []string, counts []TaskCounts) error {
+		fmt.Fprintln(out)
+		tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tTOTAL\tOPEN\tDONE\tDONE%%\tOVERDUE\n", title)
+		for i, row := range rows {
+			c := counts[i]
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.0f%%\t%d\n", row, c.Total, c.Open, c.Done, c.Percent, c.Overdue)
+		}
+		return tw.Flush()
+	}
+
+	if len(stats.Sections) > 0 {
+		var rows []string
+		var counts []TaskCounts
+		for _, section := range stats.Sections {
+			rows = append(rows, formatSectionPath(section.Path))
+			counts = append(counts, section.TaskCounts)
+		}
+		if err := writeTable("SECTION", rows, counts); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range statsBreakdownKeys {
+		values := stats.Metadata[key]
+		if len(values) == 0 {
+			continue
+		}
+
+		var rows []string
+		var counts []TaskCounts
+		for _, value := range slices.Sorted(maps.Keys(values)) {
+			rows = append(rows, value)
+			counts = append(counts, values[value])
+		}
+		if err := writeTable(strings.ToUpper(key), rows, counts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeStatsPorcelain prints the counts of a file, then of each of its sections, as porcelain records.
+// The section field is empty for the counts of the whole file.
+func writeStatsPorcelain(stats FileStats) {
+	printCounts := func(section string, c TaskCounts) {
+		printPorcelain("stats", stats.File, section, c.Total, c.Open, c.Done, c.Overdue)
+	}
+
+	printCounts("", stats.Counts)
+	for _, section := range stats.Sections {
+		printCounts(formatSectionPath(section.Path), section.TaskCounts)
+	}
+}
+
+func newStatsCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "stats [files...]",
+		Short: "Show progress statistics",
+		Long: `Show the number of open and completed tasks per file and per section,
+broken down by priority, assignee and tag, along with overdue counts.
+Tasks with created: and completed: dates also give recent activity and stale counts.
+Without arguments the file given by --file is used.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files := args
+			if len(files) == 0 {
+				files = []string{filePath}
+			}
+
+			var allStats []FileStats
+			for _, file := range files {
+				cfg, err := loadFileConfig(file)
+				if err != nil {
+					return withFile(file, err)
+				}
+				items, err := parseMarkdownFile(file)
+				if err != nil {
+					return withFile(file, err)
+				}
+				allStats = append(allStats, computeStats(file, items, newDateContext(cfg)))
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(allStats)
+			}
+
+			if porcelain != "" {
+				for _, stats := range allStats {
+					writeStatsPorcelain(stats)
+				}
+				return nil
+			}
+
+			for i, stats := range allStats {
+				if i > 0 {
+					fmt.Println()
+				}
+				if err := writeStatsTable(os.Stdout, stats); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output statistics as JSON")
+
+	return cmd
+}
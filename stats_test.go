@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeStats(t *testing.T) {
+	content := `# Work
+- [ ] Deploy priority:high assignee:bob due:2025-08-01
+- [x] Review tags:"api,infra"
+## Backend
+- [ ] API priority:low tag:api due:2025-09-01
+# Home
+- [x] Dishes
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	stats := computeStats(filename, items, DateContext{Today: wednesday})
+
+	require.Equal(t, TaskCounts{Total: 4, Open: 2, Done: 2, Percent: 50, Overdue: 1}, stats.Counts)
+
+	require.Len(t, stats.Sections, 3)
+	require.Equal(t, []string{"Work"}, stats.Sections[0].Path)
+	require.Equal(t, 2, stats.Sections[0].Total)
+	require.Equal(t, []string{"Work", "Backend"}, stats.Sections[1].Path)
+	require.Equal(t, 0, stats.Sections[1].Overdue)
+	require.Equal(t, []string{"Home"}, stats.Sections[2].Path)
+	require.Equal(t, 100.0, stats.Sections[2].Percent)
+
+	require.Equal(t, 1, stats.Metadata["priority"]["high"].Overdue)
+	require.Equal(t, 1, stats.Metadata["assignee"]["bob"].Open)
+	require.Equal(t, TaskCounts{Total: 2, Open: 1, Done: 1, Percent: 50}, stats.Metadata["tag"]["api"])
+	require.Equal(t, 1, stats.Metadata["tag"]["infra"].Done)
+}
+
+func TestComputeStats_NoSection(t *testing.T) {
+	items := []Item{
+		{Type: TypeTask, Content: "Loose task", Checked: func() *bool { b := false; return &b }()},
+	}
+
+	stats := computeStats("TODO.md", items, DateContext{Today: time.Now()})
+	require.Len(t, stats.Sections, 1)
+	require.Empty(t, stats.Sections[0].Path)
+	require.Equal(t, "(no section)", formatSectionPath(stats.Sections[0].Path))
+}
+
+func TestWriteStatsTable(t *testing.T) {
+	items := []Item{
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		{Type: TypeTask, Content: "Task", Checked: func() *bool { b := true; return &b }(), Metadata: map[string]string{"priority": "A"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeStatsTable(&buf, computeStats("TODO.md", items, DateContext{Today: time.Now()})))
+
+	output := buf.String()
+	require.Contains(t, output, "TODO.md: 1 tasks, 0 open, 1 done (100%), 0 overdue")
+	require.Contains(t, output, "SECTION")
+	require.Contains(t, output, "PRIORITY")
+	require.NotContains(t, output, "ASSIGNEE")
+	require.NotContains(t, output, "\033[")
+}
+
+func TestComputeStats_PriorityAlias(t *testing.T) {
+	content := `- [ ] Short p:high
+- [x] Long priority:high
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	stats := computeStats(filename, items, DateContext{Today: wednesday})
+	require.Equal(t, TaskCounts{Total: 2, Open: 1, Done: 1, Percent: 50}, stats.Metadata["priority"]["high"])
+	require.NotContains(t, stats.Metadata, "p")
+}
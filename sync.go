@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// syncChange is a change made by a sync command, on either side
+type syncChange struct {
+	Action  string // What was done, such as "pushed" or "pulled"
+	Key     string // Identifier of the task on the remote side
+	Content string // Description of the task
+	Detail  string // How a conflict was resolved, empty otherwise
+}
+
+// readSyncState reads the JSON sync state at path into state, leaving it empty if there is none
+func readSyncState(path string, state any) error {
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return errorf(ExitParse, "invalid sync state '%s': %w", path, err)
+	}
+	return nil
+}
+
+// writeSyncState writes the sync state as JSON to path
+func writeSyncState(path string, state any) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return nil
+}
+
+// printSyncReport prints the changes made by syncing with remote
+func printSyncReport(remote string, changes []syncChange) error {
+	if porcelain != "" {
+		for _, change := range changes {
+			printPorcelain("sync", change.Action, change.Key, change.Content, change.Detail)
+		}
+		return nil
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("Already in sync with %s\n", remote)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, change := range changes {
+		line := change.Action + "\t" + change.Content
+		if change.Detail != "" {
+			line += " (" + change.Detail + ")"
+		}
+		fmt.Fprintln(tw, line)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("Synced %d change(s) with %s\n", len(changes), remote)
+	return nil
+}
+
+func newSyncCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Synchronize tasks with a remote service",
+		Long:  "Synchronize the tasks of the markdown file with a remote service, in both directions.",
+	}
+
+	cmd.AddCommand(
+		newSyncCalDAVCommand(),
+		newSyncGitHubCommand(),
+	)
+
+	return cmd
+}
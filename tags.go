@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// tagRegex matches #tag and @person tokens starting a word.
+// Tokens made only of digits, such as issue numbers (#42), are not tags.
+var tagRegex = regexp.MustCompile(`(^|\s)([#@][\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+
+// parseTags returns the #tag and @person tokens of a task description, lowercased and without duplicates
+func parseTags(content string) []string {
+	var tags []string
+	for _, match := range tagRegex.FindAllStringSubmatch(content, -1) {
+		tag := strings.ToLower(strings.TrimRight(match[2], "/-"))
+		if strings.Trim(tag[1:], "0123456789") == "" {
+			continue
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// normalizeTag adds the # prefix to a tag given without # or @
+func normalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag != "" && tag[0] != '#' && tag[0] != '@' {
+		tag = "#" + tag
+	}
+	return tag
+}
+
+// hasTags reports whether the item is a task carrying all the tags
+func hasTags(item Item, tags []string) bool {
+	if item.Type != TypeTask {
+		return false
+	}
+	for _, tag := range tags {
+		if !slices.Contains(item.Tags, normalizeTag(tag)) {
+			return false
+		}
+	}
+	return true
+}
+
+// highlightTags colors the tags of a task description.
+// base is the color the rest of the description is shown in, restored after each tag.
+func highlightTags(content, base string) string {
+	return tagRegex.ReplaceAllStringFunc(content, func(match string) string {
+		trimmed := strings.TrimLeft(match, " \t")
+		space := match[:len(match)-len(trimmed)]
+		if strings.Trim(trimmed[1:], "0123456789") == "" {
+			return match
+		}
+
+		color := "\033[36m" // Cyan for #tags
+		if trimmed[0] == '@' {
+			color = "\033[35m" // Magenta for @mentions
+		}
+		return space + color + trimmed + "\033[0m" + base
+	})
+}
+
+// countTags counts the open and completed tasks carrying each tag
+func countTags(items []Item, dates DateContext) map[string]TaskCounts {
+	counts := make(map[string]TaskCounts)
+	for _, item := range items {
+		if item.Type != TypeTask {
+			continue
+		}
+		for _, tag := range item.Tags {
+			c := counts[tag]
+			c.add(item, dates)
+			counts[tag] = c
+		}
+	}
+	return counts
+}
+
+func newTagsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tags",
+		Short: "List tags and mentions",
+		Long: `List every #tag and @person used in task descriptions with the number of open and completed tasks.
+Use 'tasks ls --tag' or 'tasks search --tag' to show the tasks carrying a tag.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadFileConfig(filePath)
+			if err != nil {
+				return err
+			}
+			items, err := parseMarkdownFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			counts := countTags(items, newDateContext(cfg))
+			if porcelain != "" {
+				for _, tag := range slices.Sorted(maps.Keys(counts)) {
+					printPorcelain("tag", tag, counts[tag].Open, counts[tag].Done)
+				}
+				return nil
+			}
+			if len(counts) == 0 {
+				fmt.Println("No tags found")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "TAG\tOPEN\tDONE")
+			for _, tag := range slices.Sorted(maps.Keys(counts)) {
+				fmt.Fprintf(tw, "%s\t%d\t%d\n", tag, counts[tag].Open, counts[tag].Done)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+// completeTags returns shell completions for the tags used in the file
+func completeTags(toComplete string) ([]string, cobra.ShellCompDirective) {
+	items, err := parseMarkdownFile(filePath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, tag := range slices.Sorted(maps.Keys(countTags(items, newDateContext(FileConfig{})))) {
+		if strings.HasPrefix(tag, toComplete) {
+			completions = append(completions, tag)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
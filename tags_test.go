@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTags(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{"Review PR with @Alice #code", []string{"@alice", "#code"}},
+		{"#urgent fix the build", []string{"#urgent"}},
+		{"Fix issue #42", nil},
+		{"Tagged #v2 and #front-end/css.", []string{"#v2", "#front-end/css"}},
+		{"Email me@example.com about C#", nil},
+		{"Same #tag twice #Tag", []string{"#tag"}},
+		{"No tags here", nil},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, parseTags(tc.input), "Input: %s", tc.input)
+	}
+}
+
+func TestParseMarkdownFile_Tags(t *testing.T) {
+	content := `# Project #notatag
+- [ ] #urgent Fix build @bob
+- [x] Plain task due:2025-08-15
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	require.Equal(t, TypeSection, items[0].Type, "Headings are not affected by tags")
+	require.Equal(t, "Project #notatag", items[0].Content)
+	require.Empty(t, items[0].Tags)
+
+	require.Equal(t, "#urgent Fix build @bob", items[1].Content)
+	require.Equal(t, []string{"#urgent", "@bob"}, items[1].Tags)
+	require.Empty(t, items[2].Tags)
+}
+
+func TestHasTags(t *testing.T) {
+	item := Item{Type: TypeTask, Tags: []string{"#code", "@alice"}}
+
+	require.True(t, hasTags(item, []string{"code"}), "Tags without prefix are #tags")
+	require.True(t, hasTags(item, []string{"#Code", "@alice"}))
+	require.False(t, hasTags(item, []string{"code", "@bob"}))
+	require.False(t, hasTags(item, []string{"alice"}))
+	require.False(t, hasTags(Item{Type: TypeSection}, nil))
+}
+
+func TestHighlightTags(t *testing.T) {
+	require.Equal(t,
+		"Ask \033[35m@alice\033[0m about \033[36m#code\033[0m, not #42",
+		highlightTags("Ask @alice about #code, not #42", ""))
+
+	require.Equal(t,
+		"Write \033[36m#docs\033[0m\033[93m now",
+		highlightTags("Write #docs now", "\033[93m"), "The base color is restored after a tag")
+}
+
+func TestCountTags(t *testing.T) {
+	content := `- [ ] Task #work @alice
+- [x] Done #work
+- [ ] Other #home
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	counts := countTags(items, DateContext{Today: wednesday, WeekStart: time.Monday})
+	require.Len(t, counts, 3)
+	require.Equal(t, 1, counts["#work"].Open)
+	require.Equal(t, 1, counts["#work"].Done)
+	require.Equal(t, 1, counts["@alice"].Open)
+	require.Equal(t, 1, counts["#home"].Total)
+}
+
+func TestBreakdownValues_InlineTags(t *testing.T) {
+	item := Item{
+		Type:     TypeTask,
+		Metadata: map[string]string{"tag": "work", "assignee": "carol"},
+		Tags:     []string{"#work", "#code", "@alice"},
+	}
+
+	require.Equal(t, []string{"work", "code"}, breakdownValues(item, "tag"))
+	require.Equal(t, []string{"carol", "alice"}, breakdownValues(item, "assignee"))
+}
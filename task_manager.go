@@ -38,10 +38,17 @@ func createEmptyFile(filePath string) error {
 type TaskManager struct {
 	FilePath string
 	Items    []Item
+	Config   FileConfig
 }
 
-// Load reads and parses the markdown file
+// Load reads and parses the markdown file and its config
 func (tm *TaskManager) Load() error {
+	cfg, err := loadFileConfig(tm.FilePath)
+	if err != nil {
+		return err
+	}
+	tm.Config = cfg
+
 	items, err := parseMarkdownFile(tm.FilePath)
 
 	switch {
@@ -72,7 +79,7 @@ func (tm *TaskManager) Save() error {
 // GetItem returns the item at the specified index (0-based)
 func (tm *TaskManager) GetItem(index int) (*Item, error) {
 	if index < 0 || index >= len(tm.Items) {
-		return nil, fmt.Errorf("invalid item index: %d", index)
+		return nil, itemErrorf(ExitNotFound, index+1, "invalid item index: %d", index)
 	}
 	return &tm.Items[index], nil
 }
@@ -85,17 +92,50 @@ func (tm *TaskManager) ToggleTask(index int, completed bool) error {
 	}
 
 	if item.Type != TypeTask {
-		return fmt.Errorf("item at index %d is not a task", index)
+		return itemErrorf(ExitWrongType, index+1, "item at index %d is not a task", index)
 	}
 
 	*item.Checked = completed
 	return nil
 }
 
+// SetMetadata sets and removes metadata keys on a task
+func (tm *TaskManager) SetMetadata(index int, set map[string]string, unset []string) error {
+	item, err := tm.GetItem(index)
+	if err != nil {
+		return err
+	}
+
+	if item.Type != TypeTask {
+		return itemErrorf(ExitWrongType, index+1, "item at index %d is not a task", index)
+	}
+
+	if item.Metadata == nil {
+		item.Metadata = make(map[string]string)
+	}
+
+	// New keys follow the emoji syntax of tasks written with it
+	if len(item.EmojiKeys) > 0 {
+		for key, value := range set {
+			_, exists := item.Metadata[key]
+			if _, ok := formatEmojiField(key, value); ok && !exists && !slices.Contains(item.EmojiKeys, key) {
+				item.EmojiKeys = append(item.EmojiKeys, key)
+			}
+		}
+	}
+
+	maps.Copy(item.Metadata, set)
+	for _, key := range unset {
+		delete(item.Metadata, key)
+		item.EmojiKeys = slices.DeleteFunc(item.EmojiKeys, func(k string) bool { return k == key })
+	}
+	return nil
+}
+
 // RemoveItem removes an item and its children from the list
 func (tm *TaskManager) RemoveItem(index int) error {
 	if index < 0 || index >= len(tm.Items) {
-		return fmt.Errorf("invalid item index: %d", index)
+		return itemErrorf(ExitNotFound, index+1, "invalid item index: %d", index)
 	}
 
 	tm.Items = deleteItem(tm.Items, index)
@@ -119,7 +159,7 @@ func (tm *TaskManager) AddTask(description string, metadata map[string]string, a
 	} else {
 		// Insert after the specified index
 		if afterIndex < 0 || afterIndex >= len(tm.Items) {
-			return fmt.Errorf("invalid after index: %d", afterIndex)
+			return itemErrorf(ExitNotFound, afterIndex+1, "invalid after index: %d", afterIndex)
 		}
 
 		// Insert at afterIndex + 1
@@ -133,7 +173,7 @@ func (tm *TaskManager) AddTask(description string, metadata map[string]string, a
 // AddSection adds a new section to the list
 func (tm *TaskManager) AddSection(content string, level int, afterIndex int) error {
 	if level < 1 || level > 6 {
-		return fmt.Errorf("invalid section level: %d (must be 1-6)", level)
+		return errorf(ExitUsage, "invalid section level: %d (must be 1-6)", level)
 	}
 
 	newSection := Item{
@@ -151,7 +191,7 @@ func (tm *TaskManager) AddSection(content string, level int, afterIndex int) err
 	} else {
 		// Insert after the specified index
 		if afterIndex < 0 || afterIndex >= len(tm.Items) {
-			return fmt.Errorf("invalid after index: %d", afterIndex)
+			return itemErrorf(ExitNotFound, afterIndex+1, "invalid after index: %d", afterIndex)
 		}
 
 		// Insert at afterIndex + 1
@@ -196,28 +236,19 @@ func saveToFile(filePath string, items []Item) error {
 			}
 
 		case TypeTask:
-			// Format task item without any indentation
+			// Format task item, keeping its indentation so subtasks stay nested
 			checkBox := "[ ]"
 			if item.Checked != nil && *item.Checked {
 				checkBox = "[x]"
 			}
 
 			// Build the content with metadata
-			content := item.Content
+			content := escapeDescription(item.Content)
 			if len(item.Metadata) > 0 {
-				// Add metadata to the end of the content in sorted order
-				keys := slices.Sorted(maps.Keys(item.Metadata))
-				for _, key := range keys {
-					value := item.Metadata[key]
-					// Quote values that contain spaces
-					if strings.Contains(value, " ") {
-						value = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
-					}
-					content += " " + key + ":" + value
-				}
+				content += " " + formatTaskMetadata(item)
 			}
 
-			line = "- " + checkBox + " " + content
+			line = strings.Repeat(" ", item.Level) + "- " + checkBox + " " + content
 			buf.WriteString(line)
 			buf.WriteString("\n")
 
@@ -239,3 +270,17 @@ func saveToFile(filePath string, items []Item) error {
 
 	return nil
 }
+
+// needsQuoting reports whether a metadata value must be quoted to be parsed back as is,
+// which includes any value with a colon such as a time or a URL
+func needsQuoting(value string) bool {
+	return value == "" || !isIdentifier(value)
+}
+
+// formatMetadataValue quotes a metadata value if needed, escaping backslashes and quotes
+func formatMetadataValue(value string) string {
+	if !needsQuoting(value) {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
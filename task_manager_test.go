@@ -186,3 +186,80 @@ func TestTaskManager_GetItem(t *testing.T) {
 	_, err = tm.GetItem(-1)
 	require.Error(t, err)
 }
+
+func TestTaskManager_SetMetadata(t *testing.T) {
+	content := `# Section
+- [ ] Task priority:low
+`
+	filename := createTestFile(t, content)
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+
+	err = tm.SetMetadata(1, map[string]string{"priority": "high", "due": "2025-08-15"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"priority": "high", "due": "2025-08-15"}, tm.Items[1].Metadata)
+
+	err = tm.SetMetadata(1, nil, []string{"priority"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"due": "2025-08-15"}, tm.Items[1].Metadata)
+
+	// Sections have no metadata
+	err = tm.SetMetadata(0, map[string]string{"priority": "high"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not a task")
+
+	err = tm.SetMetadata(5, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid item index")
+}
+
+func TestTaskManager_SaveQuotesMetadata(t *testing.T) {
+	content := `- [ ] Deploy after:"build,test" note:"say \"hi\"" path:"C:\\temp"
+`
+	filename := createTestFile(t, content)
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	require.NoError(t, tm.Save())
+
+	tm2, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	require.Equal(t, "Deploy", tm2.Items[0].Content)
+	require.Equal(t, tm.Items[0].Metadata, tm2.Items[0].Metadata)
+	require.Equal(t, "build,test", tm2.Items[0].Metadata["after"])
+	require.Equal(t, `say "hi"`, tm2.Items[0].Metadata["note"])
+	require.Equal(t, `C:\temp`, tm2.Items[0].Metadata["path"])
+}
+
+func TestTaskManager_SaveQuotesColons(t *testing.T) {
+	filename := createTestFile(t, "- [ ] Meeting\n")
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	metadata := map[string]string{"at": "10:30", "link": "https://example.com/a?b=c"}
+	require.NoError(t, tm.SetMetadata(0, metadata, nil))
+	require.NoError(t, tm.Save())
+	require.Equal(t, "- [ ] Meeting at:\"10:30\" link:\"https://example.com/a?b=c\"\n", readTestFile(t, filename))
+
+	tm2, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	require.Equal(t, "Meeting", tm2.Items[0].Content)
+	require.Equal(t, metadata, tm2.Items[0].Metadata)
+}
+
+func TestTaskManager_SaveKeepsMetadataLikeTitle(t *testing.T) {
+	filename := createTestFile(t, "")
+
+	tm, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	content, metadata := parseTitle("status:blocked")
+	require.NoError(t, tm.AddTask(content, metadata, -1))
+	require.NoError(t, tm.Save())
+	require.Equal(t, "- [ ] status\\:blocked\n", readTestFile(t, filename))
+
+	tm2, err := NewTaskManager(filename)
+	require.NoError(t, err)
+	require.Equal(t, "status:blocked", tm2.Items[0].Content)
+	require.Empty(t, tm2.Items[0].Metadata)
+}
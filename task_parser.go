@@ -1,6 +1,7 @@
 package main
 
 import (
+	"slices"
 	"strings"
 	"unicode"
 )
@@ -17,6 +18,7 @@ type ParsedTask struct {
 	Description string            // Clean task description without metadata
 	Completed   bool              // Task completion status
 	Metadata    map[string]string // Key-value metadata pairs
+	EmojiKeys   []string          // Metadata keys given in Obsidian Tasks emoji syntax
 }
 
 // parseTask parses a single task line and extracts description, completion status, and metadata
@@ -44,6 +46,41 @@ func parseTask(taskLine string) ParsedTask {
 	return result
 }
 
+// parseTitle parses the title of a task from another application like a task line, so that the
+// key:value pairs it contains become metadata and the description reads the same once saved.
+// A title made only of key:value pairs is kept whole as the description, saved with its colons escaped.
+func parseTitle(title string) (string, map[string]string) {
+	parsed := parseTask("- [ ] " + title)
+	if parsed.Description == "" {
+		return title, nil
+	}
+	return parsed.Description, parsed.Metadata
+}
+
+// escapeDescription escapes the colon of the words of a description that would be read back as
+// key:value metadata, e.g. "key:value" becomes `key\:value`, so that saving the description keeps it whole
+func escapeDescription(description string) string {
+	words := strings.Split(description, " ")
+	for i, word := range words {
+		p := &TaskParser{input: word, len: len(word)}
+		if _, _, ok := p.parseMetadata(); ok {
+			colon := strings.IndexByte(word, ':')
+			words[i] = word[:colon] + `\` + word[colon:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// unescapeWord reverts escapeDescription for a word of a description
+func unescapeWord(word string) string {
+	p := &TaskParser{input: word, len: len(word)}
+	key := p.parseIdentifier()
+	if key == "" || !unicode.IsLetter(rune(key[0])) || !strings.HasPrefix(word[p.pos:], `\:`) {
+		return word
+	}
+	return key + word[p.pos+1:]
+}
+
 // parseTaskPrefix parses "- [x]" or "- [ ]" and sets completion status
 func (p *TaskParser) parseTaskPrefix(result *ParsedTask) bool {
 	p.skipWhitespace()
@@ -92,6 +129,15 @@ func (p *TaskParser) parseContent(result *ParsedTask) {
 	for p.pos < p.len {
 		p.skipWhitespace()
 
+		// Try to parse an Obsidian Tasks emoji field
+		if key, value, ok := p.parseEmojiField(); ok {
+			result.Metadata[key] = value
+			if !slices.Contains(result.EmojiKeys, key) {
+				result.EmojiKeys = append(result.EmojiKeys, key)
+			}
+			continue
+		}
+
 		// Try to parse metadata key:value pair
 		if key, value, ok := p.parseMetadata(); ok {
 			result.Metadata[key] = value
@@ -99,7 +145,7 @@ func (p *TaskParser) parseContent(result *ParsedTask) {
 		}
 
 		// Parse regular word (will always succeed for non-whitespace characters)
-		word := p.parseWord()
+		word := unescapeWord(p.parseWord())
 		tokens = append(tokens, word)
 	}
 
@@ -144,13 +190,13 @@ func (p *TaskParser) parseMetadata() (key, value string, ok bool) {
 	return key, value, true
 }
 
-// parseIdentifier parses an identifier (letters, digits, underscore, hyphen, dot)
+// parseIdentifier parses an identifier (letters, digits, underscore, hyphen, dot, plus)
 func (p *TaskParser) parseIdentifier() string {
 	start := p.pos
 
 	for p.pos < p.len {
 		ch := rune(p.input[p.pos])
-		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' && ch != '-' && ch != '.' {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' && ch != '-' && ch != '.' && ch != '+' {
 			break
 		}
 		p.pos++
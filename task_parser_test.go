@@ -222,6 +222,41 @@ func TestParseTask_WhitespaceHandling(t *testing.T) {
 	})
 }
 
+func TestParseTitle(t *testing.T) {
+	description, metadata := parseTitle("Support key:value syntax")
+	require.Equal(t, "Support syntax", description)
+	require.Equal(t, map[string]string{"key": "value"}, metadata)
+
+	description, metadata = parseTitle("Fix the build")
+	require.Equal(t, "Fix the build", description)
+	require.Empty(t, metadata)
+
+	description, metadata = parseTitle("key:value")
+	require.Equal(t, "key:value", description, "Titles made only of metadata are kept whole")
+	require.Nil(t, metadata)
+}
+
+func TestEscapeDescription(t *testing.T) {
+	testCases := []struct {
+		description string
+		escaped     string
+	}{
+		{"key:value", `key\:value`},
+		{"a:b and c:d:e", `a\:b and c\:d:e`},
+		{"See https://example.com at 10:30", "See https://example.com at 10:30"},
+		{"Plain words", "Plain words"},
+	}
+
+	for _, tc := range testCases {
+		escaped := escapeDescription(tc.description)
+		require.Equal(t, tc.escaped, escaped, "Description: %s", tc.description)
+
+		result := parseTask("- [ ] " + escaped)
+		require.Equal(t, tc.description, result.Description, "Description: %s", tc.description)
+		require.Empty(t, result.Metadata, "Description: %s", tc.description)
+	}
+}
+
 // Tests for parseTaskPrefix edge cases to improve coverage
 func TestParseTaskPrefix_EdgeCases(t *testing.T) {
 	t.Run("incomplete checkbox - ends at opening bracket", func(t *testing.T) {
@@ -352,3 +387,10 @@ func TestParseTask_RealWorldExamples(t *testing.T) {
 		require.Equal(t, "in progress", result.Metadata["status"])
 	})
 }
+
+func TestParseTask_RelativeDateValues(t *testing.T) {
+	result := parseTask("- [ ] Call vendor due:+3d remind:-1d")
+	require.Equal(t, "Call vendor", result.Description)
+	require.Equal(t, "+3d", result.Metadata["due"])
+	require.Equal(t, "-1d", result.Metadata["remind"])
+}
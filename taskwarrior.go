@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Taskwarrior exports tasks as a JSON array with one object per task (`task export`):
+//
+//	{"uuid":"…","description":"Fix the build","status":"pending","project":"Work.Backend",
+//	 "tags":["ci"],"priority":"H","due":"20250815T000000Z","annotations":[{"entry":"…","description":"See #12"}]}
+//
+// The dot-separated project gives the section path and tags are added to the description.
+// Markdown tasks have no body, so annotations are joined into a note: value, separated by "; ".
+// Semicolons and backslashes of the annotations are escaped with a backslash so that they are split back the same.
+// Imported tasks keep their UUID as uuid: metadata, so importing them again updates them.
+
+// taskwarriorTimeLayout is the layout of Taskwarrior dates, always in UTC
+const taskwarriorTimeLayout = "20060102T150405Z"
+
+// taskwarriorNoteSeparator separates annotations in note: values
+const taskwarriorNoteSeparator = "; "
+
+// taskwarriorNoteEscaper escapes the annotations joined into note: values
+var taskwarriorNoteEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`)
+
+// splitTaskwarriorNote splits a note: value into annotations at the separators, reverting taskwarriorNoteEscaper.
+// Other backslashes are kept as is, as in notes written by hand.
+func splitTaskwarriorNote(note string) []string {
+	var annotations []string
+	var annotation strings.Builder
+	for i := 0; i < len(note); i++ {
+		switch {
+		case note[i] == '\\' && i+1 < len(note) && (note[i+1] == '\\' || note[i+1] == ';'):
+			i++
+			annotation.WriteByte(note[i])
+		case strings.HasPrefix(note[i:], taskwarriorNoteSeparator):
+			annotations = append(annotations, annotation.String())
+			annotation.Reset()
+			i += len(taskwarriorNoteSeparator) - 1
+		default:
+			annotation.WriteByte(note[i])
+		}
+	}
+	return append(annotations, annotation.String())
+}
+
+// taskwarriorTask is a task as exported by Taskwarrior, limited to the attributes that have a markdown equivalent
+type taskwarriorTask struct {
+	UUID        string                  `json:"uuid,omitempty"`
+	Description string                  `json:"description"`
+	Status      string                  `json:"status"`
+	Entry       string                  `json:"entry,omitempty"`
+	End         string                  `json:"end,omitempty"`
+	Due         string                  `json:"due,omitempty"`
+	Scheduled   string                  `json:"scheduled,omitempty"`
+	Wait        string                  `json:"wait,omitempty"`
+	Project     string                  `json:"project,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Priority    string                  `json:"priority,omitempty"`
+	Annotations []taskwarriorAnnotation `json:"annotations,omitempty"`
+}
+
+type taskwarriorAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// taskwarriorDateKeys maps the date attributes of Taskwarrior to metadata keys
+var taskwarriorDateKeys = []struct {
+	key   string
+	field func(task *taskwarriorTask) *string
+}{
+	{"created", func(task *taskwarriorTask) *string { return &task.Entry }},
+	{"completed", func(task *taskwarriorTask) *string { return &task.End }},
+	{"due", func(task *taskwarriorTask) *string { return &task.Due }},
+	{"scheduled", func(task *taskwarriorTask) *string { return &task.Scheduled }},
+	{"wait", func(task *taskwarriorTask) *string { return &task.Wait }},
+}
+
+// taskwarriorUUID returns the UUID of a task: its uuid: metadata, kept from an import,
+// or else a UUID derived from its section path and description
+func taskwarriorUUID(item Item, path []string) string {
+	if uuid, ok := item.Metadata["uuid"]; ok {
+		return uuid
+	}
+	return nameUUID(strings.Join(slices.Concat(path, []string{item.Content}), "\x00"))
+}
+
+// taskwarriorUUIDSuffix returns the UUID of the nth task having the given UUID, derived from it
+func taskwarriorUUIDSuffix(uuid string, n int) string {
+	return nameUUID(fmt.Sprintf("%s\x00%d", uuid, n))
+}
+
+// nameUUID returns a name-based UUID
+func nameUUID(name string) string {
+	sum := sha1.Sum([]byte(name))
+	sum[6] = sum[6]&0x0f | 0x50 // Version 5, name-based with SHA-1
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// taskwarriorPriority converts a priority to H, M or L, if possible
+func taskwarriorPriority(value string) (string, bool) {
+	rank, ok := priorityRank(value)
+	switch {
+	case !ok || rank < 0:
+		return "", false
+	case rank <= 1:
+		return "H", true
+	case rank == 2:
+		return "M", true
+	default:
+		return "L", true
+	}
+}
+
+// taskPriorityFromTaskwarrior converts a Taskwarrior priority to a task priority
+func taskPriorityFromTaskwarrior(value string) (string, bool) {
+	switch value {
+	case "H":
+		return "high", true
+	case "M":
+		return "medium", true
+	case "L":
+		return "low", true
+	}
+	return "", false
+}
+
+// formatTaskwarriorDate converts a metadata date to a Taskwarrior date, midnight in local time
+func formatTaskwarriorDate(value string) (string, bool) {
+	date, ok := newDateContext(FileConfig{}).Parse(value)
+	if !ok {
+		return "", false
+	}
+	return date.UTC().Format(taskwarriorTimeLayout), true
+}
+
+// parseTaskwarriorDate converts a Taskwarrior date to a metadata date in local time
+func parseTaskwarriorDate(value string) (string, bool) {
+	t, err := time.Parse(taskwarriorTimeLayout, value)
+	if err != nil {
+		return "", false
+	}
+	return t.Local().Format(time.DateOnly), true
+}
+
+// parseTaskwarriorTask converts a Taskwarrior task to a task and its section path
+func parseTaskwarriorTask(task taskwarriorTask) (Item, []string) {
+	metadata := make(map[string]string)
+	if task.UUID != "" {
+		metadata["uuid"] = task.UUID
+	}
+	for _, date := range taskwarriorDateKeys {
+		if value, ok := parseTaskwarriorDate(*date.field(&task)); ok {
+			metadata[date.key] = value
+		}
+	}
+	if priority, ok := taskPriorityFromTaskwarrior(task.Priority); ok {
+		metadata["priority"] = priority
+	}
+
+	var notes []string
+	for _, annotation := range task.Annotations {
+		if note := strings.Join(strings.Fields(annotation.Description), " "); note != "" {
+			notes = append(notes, taskwarriorNoteEscaper.Replace(note))
+		}
+	}
+	if len(notes) > 0 {
+		metadata["note"] = strings.Join(notes, taskwarriorNoteSeparator)
+	}
+
+	content, titleMetadata := parseTitle(strings.Join(strings.Fields(task.Description), " "))
+	for key, value := range titleMetadata {
+		if _, ok := metadata[key]; !ok {
+			metadata[key] = value
+		}
+	}
+	tags := parseTags(content)
+	for _, tag := range task.Tags {
+		if tag = normalizeTag(tag); tag != "" && !slices.Contains(tags, tag) {
+			content += " " + tag
+			tags = append(tags, tag)
+		}
+	}
+
+	if task.Status != "completed" {
+		delete(metadata, "completed")
+	}
+	checked := task.Status == "completed"
+
+	var path []string
+	for name := range strings.SplitSeq(task.Project, ".") {
+		if name = strings.TrimSpace(name); name != "" {
+			path = append(path, name)
+		}
+	}
+
+	return Item{
+		Type:     TypeTask,
+		Content:  content,
+		Checked:  &checked,
+		Metadata: metadata,
+		Tags:     tags,
+	}, path
+}
+
+// readTaskwarriorTasks reads the output of `task export`: a JSON array, or one object per line as older versions wrote
+func readTaskwarriorTasks(r io.Reader) ([]taskwarriorTask, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading taskwarrior export: %w", err)
+	}
+
+	var tasks []taskwarriorTask
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, errorf(ExitParse, "invalid taskwarrior export: %w", err)
+		}
+		return tasks, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var task taskwarriorTask
+		err := decoder.Decode(&task)
+		if errors.Is(err, io.EOF) {
+			return tasks, nil
+		}
+		if err != nil {
+			return nil, errorf(ExitParse, "invalid taskwarrior export: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+}
+
+// importTaskwarrior reads Taskwarrior tasks, placing them under the sections given by their projects.
+// Deleted tasks and the templates of recurring tasks are skipped, their pending occurrences being exported too.
+func importTaskwarrior(r io.Reader) ([]Item, error) {
+	exported, err := readTaskwarriorTasks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Item
+	var paths [][]string
+	for _, task := range exported {
+		if task.Status == "deleted" || task.Status == "recurring" {
+			continue
+		}
+		item, path := parseTaskwarriorTask(task)
+		if item.Content == "" {
+			continue
+		}
+		tasks = append(tasks, item)
+		paths = append(paths, path)
+	}
+	return buildSections(tasks, paths), nil
+}
+
+// formatTaskwarriorTask converts a task with the given UUID to a Taskwarrior task, the section path becoming its project
+func formatTaskwarriorTask(item Item, uuid string, path []string) taskwarriorTask {
+	task := taskwarriorTask{
+		UUID:    uuid,
+		Status:  "pending",
+		Project: strings.Join(path, "."),
+	}
+
+	for _, date := range taskwarriorDateKeys {
+		if value, ok := formatTaskwarriorDate(item.Metadata[date.key]); ok {
+			*date.field(&task) = value
+		}
+	}
+
+	if item.Checked != nil && *item.Checked {
+		task.Status = "completed"
+		if task.End == "" {
+			task.End = clock.Now().UTC().Format(taskwarriorTimeLayout)
+		}
+	} else {
+		task.End = ""
+	}
+
+	if value, ok := metadataValue(item, "priority"); ok {
+		task.Priority, _ = taskwarriorPriority(value)
+	}
+
+	// Tags become Taskwarrior tags, without the # of the description
+	var words []string
+	for _, word := range strings.Fields(item.Content) {
+		if tags := parseTags(word); len(tags) == 1 && tags[0] == strings.ToLower(word) {
+			continue
+		}
+		words = append(words, word)
+	}
+	task.Description = strings.Join(words, " ")
+	if task.Description == "" {
+		task.Description = item.Content
+	}
+	for _, tag := range item.Tags {
+		task.Tags = append(task.Tags, strings.TrimPrefix(tag, "#"))
+	}
+
+	if note, ok := item.Metadata["note"]; ok {
+		entry := cmp.Or(task.Entry, clock.Now().UTC().Format(taskwarriorTimeLayout))
+		for _, annotation := range splitTaskwarriorNote(note) {
+			task.Annotations = append(task.Annotations, taskwarriorAnnotation{Entry: entry, Description: annotation})
+		}
+	}
+
+	return task
+}
+
+// exportTaskwarrior writes every task as a JSON array that `task import` accepts, one task per line
+func exportTaskwarrior(w io.Writer, items []Item) error {
+	paths := sectionPaths(items)
+	uuids := uniqueKeys(items, taskwarriorUUID, taskwarriorUUIDSuffix)
+
+	var lines []string
+	for i, item := range items {
+		if item.Type != TypeTask {
+			continue
+		}
+
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(formatTaskwarriorTask(item, uuids[i], paths[i])); err != nil {
+			return err
+		}
+		lines = append(lines, strings.TrimSuffix(buf.String(), "\n"))
+	}
+
+	if len(lines) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "[\n%s\n]\n", strings.Join(lines, ",\n"))
+	return err
+}
+
+// updateTaskwarriorTask updates a task imported before with the status, priority, dates and notes of its Taskwarrior task
+func updateTaskwarriorTask(existing *Item, imported Item) {
+	*existing.Checked = *imported.Checked
+	if existing.Metadata == nil {
+		existing.Metadata = make(map[string]string)
+	}
+	for _, key := range []string{"due", "completed", "scheduled", "wait", "priority", "note"} {
+		if value, ok := imported.Metadata[key]; ok {
+			existing.Metadata[key] = value
+		} else {
+			delete(existing.Metadata, key)
+		}
+	}
+}
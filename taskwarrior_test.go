@@ -0,0 +1,205 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// taskwarriorDate returns the Taskwarrior date of local midnight on the given day
+func taskwarriorDate(day string) string {
+	t, _ := time.ParseInLocation(time.DateOnly, day, time.Local)
+	return t.UTC().Format(taskwarriorTimeLayout)
+}
+
+func TestTaskwarriorUUID(t *testing.T) {
+	require.Equal(t, "abc", taskwarriorUUID(Item{Metadata: map[string]string{"uuid": "abc"}}, nil))
+
+	uuid := taskwarriorUUID(Item{Content: "Fix bug"}, []string{"Work"})
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, uuid)
+	require.Equal(t, uuid, taskwarriorUUID(Item{Content: "Fix bug"}, []string{"Work"}), "UUIDs are stable")
+	require.NotEqual(t, uuid, taskwarriorUUID(Item{Content: "Fix bug"}, []string{"Home"}))
+
+	second := taskwarriorUUIDSuffix(uuid, 2)
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, second)
+	require.NotEqual(t, uuid, second)
+	require.NotEqual(t, second, taskwarriorUUIDSuffix(uuid, 3))
+}
+
+func TestTaskwarriorPriority(t *testing.T) {
+	for value, expected := range map[string]string{"highest": "H", "high": "H", "A": "H", "medium": "M", "C": "M", "low": "L", "lowest": "L", "Z": "L"} {
+		priority, ok := taskwarriorPriority(value)
+		require.True(t, ok, value)
+		require.Equal(t, expected, priority, value)
+	}
+	_, ok := taskwarriorPriority("soon")
+	require.False(t, ok)
+}
+
+func TestImportTaskwarrior(t *testing.T) {
+	input := `[
+{"id":1,"description":"Fix the build","entry":"` + taskwarriorDate("2025-08-01") + `","modified":"20250802T100000Z","project":"Work.Backend","priority":"H","status":"pending","tags":["ci","urgent"],"due":"` + taskwarriorDate("2025-08-15") + `","uuid":"1b1f7a3e-0000-4000-8000-000000000001","annotations":[{"entry":"20250802T100000Z","description":"See the logs"},{"entry":"20250803T100000Z","description":"Ask @bob"}],"urgency":9.2},
+{"id":0,"description":"Pay rent","end":"` + taskwarriorDate("2025-08-05") + `","entry":"` + taskwarriorDate("2025-08-01") + `","status":"completed","uuid":"1b1f7a3e-0000-4000-8000-000000000002"},
+{"id":2,"description":"Water plants #garden","project":"Home","status":"waiting","wait":"` + taskwarriorDate("2025-09-01") + `","tags":["garden"],"uuid":"1b1f7a3e-0000-4000-8000-000000000003"},
+{"id":0,"description":"Old idea","status":"deleted","uuid":"1b1f7a3e-0000-4000-8000-000000000004"},
+{"id":0,"description":"Weekly review","recur":"weekly","status":"recurring","uuid":"1b1f7a3e-0000-4000-8000-000000000005"},
+{"id":3,"description":"Fix the tests","project":"Work","status":"pending","uuid":"1b1f7a3e-0000-4000-8000-000000000006"}
+]
+`
+	items, err := importTaskwarrior(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Pay rent", "Work", "Fix the tests", "Backend", "Fix the build #ci #urgent", "Home", "Water plants #garden"}, contents(items))
+	require.Equal(t, []int{0, 1, 0, 2, 0, 1, 0}, []int{items[0].Level, items[1].Level, items[2].Level, items[3].Level, items[4].Level, items[5].Level, items[6].Level})
+
+	require.True(t, *items[0].Checked)
+	require.Equal(t, map[string]string{"uuid": "1b1f7a3e-0000-4000-8000-000000000002", "created": "2025-08-01", "completed": "2025-08-05"}, items[0].Metadata)
+
+	require.False(t, *items[4].Checked)
+	require.Equal(t, map[string]string{
+		"uuid":     "1b1f7a3e-0000-4000-8000-000000000001",
+		"created":  "2025-08-01",
+		"due":      "2025-08-15",
+		"priority": "high",
+		"note":     "See the logs; Ask @bob",
+	}, items[4].Metadata)
+	require.Equal(t, []string{"#ci", "#urgent"}, items[4].Tags)
+
+	require.False(t, *items[6].Checked, "Waiting tasks are open")
+	require.Equal(t, "2025-09-01", items[6].Metadata["wait"])
+	require.Equal(t, []string{"#garden"}, items[6].Tags, "Tags already in the description are not added twice")
+}
+
+func TestImportTaskwarrior_Lines(t *testing.T) {
+	input := `{"description":"Call mom","status":"pending"}
+{"description":"Buy milk","status":"completed"}
+`
+	items, err := importTaskwarrior(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Call mom", "Buy milk"}, contents(items))
+
+	_, err = importTaskwarrior(strings.NewReader(`[{"description":`))
+	require.Error(t, err)
+	require.Equal(t, ExitParse, exitCode(err))
+}
+
+func TestImportTaskwarrior_MetadataInTitle(t *testing.T) {
+	input := `{"description":"Refactor key:value parser","status":"pending","priority":"L"}
+{"description":"Ship priority:high","status":"pending","priority":"L"}
+`
+	items, err := importTaskwarrior(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Refactor parser", "Ship"}, contents(items))
+	require.Equal(t, map[string]string{"key": "value", "priority": "low"}, items[0].Metadata)
+	require.Equal(t, map[string]string{"priority": "low"}, items[1].Metadata, "Taskwarrior fields take precedence over the title")
+}
+
+func TestExportTaskwarrior(t *testing.T) {
+	useClock(t, wednesday)
+	content := `- [ ] Call mom
+
+# Work
+
+## Backend
+
+- [x] Fix the build #ci completed:2025-08-12 priority:high uuid:abc
+- [ ] Deploy due:2025-08-15 created:2025-08-01 note:"Check the logs; Ask @bob" priority:C
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportTaskwarrior(&buf, items))
+
+	lines := strings.Split(buf.String(), "\n")
+	require.Len(t, lines, 6)
+	require.Equal(t, "[", lines[0])
+	require.Equal(t, `{"uuid":"`+taskwarriorUUID(items[0], nil)+`","description":"Call mom","status":"pending"},`, lines[1])
+	require.Equal(t, `{"uuid":"abc","description":"Fix the build","status":"completed","end":"`+taskwarriorDate("2025-08-12")+`","project":"Work.Backend","tags":["ci"],"priority":"H"},`, lines[2])
+	require.Equal(t, `{"uuid":"`+taskwarriorUUID(items[4], []string{"Work", "Backend"})+`","description":"Deploy","status":"pending","entry":"`+taskwarriorDate("2025-08-01")+`","due":"`+taskwarriorDate("2025-08-15")+`","project":"Work.Backend","priority":"M","annotations":[{"entry":"`+taskwarriorDate("2025-08-01")+`","description":"Check the logs"},{"entry":"`+taskwarriorDate("2025-08-01")+`","description":"Ask @bob"}]}`, lines[3])
+	require.Equal(t, "]", lines[4])
+
+	buf.Reset()
+	require.NoError(t, exportTaskwarrior(&buf, nil))
+	require.Equal(t, "[]\n", buf.String())
+}
+
+func TestTaskwarrior_RoundTrip(t *testing.T) {
+	content := `# Work
+- [ ] Deploy #ops due:2025-08-15 priority:high
+- [ ] Write docs
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportTaskwarrior(&buf, items))
+
+	// Taskwarrior completes a task and postpones the other one
+	remote := strings.Replace(buf.String(), `"description":"Deploy","status":"pending"`, `"description":"Deploy","status":"completed","end":"`+taskwarriorDate("2025-08-14")+`"`, 1)
+	remote = strings.Replace(remote, `"description":"Write docs","status":"pending"`, `"description":"Write docs","status":"pending","due":"`+taskwarriorDate("2025-08-20")+`"`, 1)
+
+	imported, err := importTaskwarrior(strings.NewReader(remote))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Work", "Deploy #ops", "Write docs"}, contents(imported))
+
+	remaining, updated := updateItems(items, imported, taskFormats["taskwarrior"])
+	require.Empty(t, remaining, "Known tasks are not added again")
+	require.Equal(t, 2, updated)
+
+	require.True(t, *items[1].Checked)
+	require.Equal(t, map[string]string{"due": "2025-08-15", "priority": "high", "completed": "2025-08-14"}, items[1].Metadata)
+	require.False(t, *items[2].Checked)
+	require.Equal(t, map[string]string{"due": "2025-08-20"}, items[2].Metadata)
+}
+
+func TestTaskwarrior_RoundTrip_IdenticalTasks(t *testing.T) {
+	// Completing a recurring task leaves a record with the same description
+	content := `# Home
+- [x] Water plants due:2025-08-13
+- [ ] Water plants due:2025-08-20 recur:weekly
+`
+	items, err := parseMarkdownFile(createTestFile(t, content))
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportTaskwarrior(&buf, items))
+
+	imported, err := importTaskwarrior(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Home", "Water plants", "Water plants"}, contents(imported))
+	require.Equal(t, taskwarriorUUIDSuffix(imported[1].Metadata["uuid"], 2), imported[2].Metadata["uuid"], "Identical tasks get distinct UUIDs")
+
+	remaining, updated := updateItems(items, imported, taskFormats["taskwarrior"])
+	require.Empty(t, remaining)
+	require.Equal(t, 2, updated)
+	require.True(t, *items[1].Checked)
+	require.False(t, *items[2].Checked)
+}
+
+func TestTaskwarrior_RoundTrip_Annotations(t *testing.T) {
+	remote := `[{"description":"Deploy","status":"pending","uuid":"1b1f7a3e-0000-4000-8000-000000000001","annotations":[` +
+		`{"entry":"20250802T100000Z","description":"Run a; then b"},{"entry":"20250802T100000Z","description":"Logs in C:\\temp"}]}]`
+
+	imported, err := importTaskwarrior(strings.NewReader(remote))
+	require.NoError(t, err)
+	require.Equal(t, `Run a\; then b; Logs in C:\\temp`, imported[0].Metadata["note"])
+
+	// The note survives being saved in the markdown file
+	filename := createTestFile(t, "")
+	require.NoError(t, saveToFile(filename, imported))
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, exportTaskwarrior(&buf, items))
+	require.Contains(t, buf.String(), `"description":"Run a; then b"},`)
+	require.Contains(t, buf.String(), `"description":"Logs in C:\\temp"}]`)
+}
+
+func TestSplitTaskwarriorNote(t *testing.T) {
+	require.Equal(t, []string{"Check the logs", "Ask @bob"}, splitTaskwarriorNote("Check the logs; Ask @bob"))
+	require.Equal(t, []string{"a; b", `c\d`}, splitTaskwarriorNote(`a\; b; c\\d`))
+	require.Equal(t, []string{`C:\temp`, "x;y"}, splitTaskwarriorNote(`C:\temp; x;y`), "Other backslashes and semicolons are kept")
+}
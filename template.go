@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateItem is the data an item template is executed with
+type templateItem struct {
+	Item
+	ID      int      // 1-based ID
+	Section []string // Names of the sections the item is nested in
+	Done    bool     // Whether the item is a completed task
+}
+
+// templateColors maps the color names accepted by the color template function to ANSI codes
+var templateColors = map[string]string{
+	"bold":    "1",
+	"dim":     "2",
+	"red":     "91",
+	"green":   "92",
+	"yellow":  "93",
+	"blue":    "94",
+	"magenta": "95",
+	"cyan":    "96",
+	"white":   "97",
+}
+
+// templateFuncs returns the helper functions available to item templates
+func templateFuncs(dates DateContext) template.FuncMap {
+	parseDate := func(value string) (time.Time, error) {
+		date, ok := dates.Parse(value)
+		if !ok {
+			return time.Time{}, errorf(ExitParse, "invalid date '%s'", value)
+		}
+		return date, nil
+	}
+
+	return template.FuncMap{
+		// Types
+		"isTask":    func(item templateItem) bool { return item.Type == TypeTask },
+		"isSection": func(item templateItem) bool { return item.Type == TypeSection },
+
+		// Dates
+		"today": func() string { return dates.today() },
+		"formatDate": func(layout, value string) (string, error) {
+			date, err := parseDate(value)
+			if err != nil {
+				return "", err
+			}
+			return date.Format(layout), nil
+		},
+		"daysUntil": func(value string) (int, error) {
+			date, err := parseDate(value)
+			if err != nil {
+				return 0, err
+			}
+			return int(date.Sub(startOfDay(dates.Today)).Round(24*time.Hour).Hours() / 24), nil
+		},
+		"dueStatus": func(item templateItem) string {
+			if status := dates.DueStatus(item.Item); status != DueNone {
+				return strings.ToLower(status.String())
+			}
+			return ""
+		},
+		"overdue": func(item templateItem) bool { return dates.IsOverdue(item.Item) },
+
+		// Sections
+		"path": func(section []string) string { return strings.Join(section, " / ") },
+		"join": func(sep string, values []string) string { return strings.Join(values, sep) },
+
+		// Colors, only applied when color output is enabled
+		"color": func(name string, text any) (string, error) {
+			code, ok := templateColors[name]
+			if !ok {
+				return "", errorf(ExitUsage, "unknown color '%s'", name)
+			}
+			if !shouldUseColor() {
+				return fmt.Sprint(text), nil
+			}
+			return "\033[" + code + "m" + fmt.Sprint(text) + "\033[0m", nil
+		},
+	}
+}
+
+// parseItemTemplate parses an item template given inline or read from a file
+func parseItemTemplate(text, file string, dates DateContext) (*template.Template, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		text = strings.TrimSuffix(string(data), "\n")
+	}
+
+	tmpl, err := template.New("item").Option("missingkey=zero").Funcs(templateFuncs(dates)).Parse(text)
+	if err != nil {
+		return nil, errorf(ExitParse, "invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeTemplate renders every item that is not hidden through the template, one line per item.
+// Items rendering to an empty string are skipped, so templates can filter items.
+func writeTemplate(w io.Writer, tmpl *template.Template, items []Item, hidden []bool) error {
+	paths := sectionPaths(items)
+
+	var buf strings.Builder
+	for i, item := range items {
+		if hidden[i] {
+			continue
+		}
+
+		data := templateItem{
+			Item:    item,
+			ID:      i + 1,
+			Section: paths[i],
+			Done:    item.Checked != nil && *item.Checked,
+		}
+		if item.Type == TypeSection {
+			data.Section = paths[i][:len(paths[i])-1] // A section's path ends with its own name
+		}
+
+		buf.Reset()
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("rendering item %d: %w", i+1, err)
+		}
+		if buf.Len() == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, buf.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func renderTemplate(t *testing.T, text, content string) string {
+	t.Helper()
+
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	tmpl, err := parseItemTemplate(text, "", DateContext{Today: wednesday, WeekStart: time.Monday})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTemplate(&buf, tmpl, items, make([]bool, len(items))))
+	return buf.String()
+}
+
+func TestWriteTemplate(t *testing.T) {
+	content := `# Work
+- [ ] Ship release due:2025-08-12
+- [x] Tag build
+## Meetings
+- [ ] Standup due:2025-08-20
+`
+
+	require.Equal(t,
+		"1 Work \n2 Ship release 2025-08-12\n3 Tag build \n4 Meetings \n5 Standup 2025-08-20\n",
+		renderTemplate(t, `{{.ID}} {{.Content}} {{index .Metadata "due"}}`, content))
+
+	require.Equal(t,
+		"2 Work: Ship release\n5 Work / Meetings: Standup\n",
+		renderTemplate(t, `{{if and (isTask .) (not .Done)}}{{.ID}} {{path .Section}}: {{.Content}}{{end}}`, content),
+		"Items rendering to nothing are skipped")
+
+	require.Equal(t,
+		"Ship release -1 overdue Tue 12\nStandup 7 later Wed 20\n",
+		renderTemplate(t, `{{with index .Metadata "due"}}{{$.Content}} {{daysUntil .}} {{dueStatus $}} {{formatDate "Mon 2" .}}{{end}}`, content))
+
+	require.Equal(t,
+		"Work\nMeetings Work\n",
+		renderTemplate(t, `{{if isSection .}}{{.Content}}{{with .Section}} {{join "," .}}{{end}}{{end}}`, content))
+}
+
+func TestWriteTemplate_Color(t *testing.T) {
+	oldColorMode := colorMode
+	t.Cleanup(func() { colorMode = oldColorMode })
+
+	colorMode = "always"
+	require.Equal(t, "\033[91mTask\033[0m\n", renderTemplate(t, `{{color "red" .Content}}`, "- [ ] Task\n"))
+
+	colorMode = "never"
+	require.Equal(t, "Task\n", renderTemplate(t, `{{color "red" .Content}}`, "- [ ] Task\n"))
+}
+
+func TestWriteTemplate_Errors(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+
+	_, err := parseItemTemplate("{{.ID", "", dates)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid template")
+
+	tmpl, err := parseItemTemplate(`{{color "plaid" .Content}}`, "", dates)
+	require.NoError(t, err)
+
+	items := []Item{{Type: TypeTask, Content: "Task", Checked: new(bool)}}
+	err = writeTemplate(&bytes.Buffer{}, tmpl, items, []bool{false})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown color 'plaid'")
+}
+
+func TestParseItemTemplate_File(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "item.tmpl")
+	require.NoError(t, os.WriteFile(file, []byte("{{.ID}}: {{.Content}}\n"), 0o644))
+
+	tmpl, err := parseItemTemplate("", file, DateContext{Today: wednesday, WeekStart: time.Monday})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	items := []Item{{Type: TypeTask, Content: "Task", Checked: new(bool)}}
+	require.NoError(t, writeTemplate(&buf, tmpl, items, []bool{false}))
+	require.Equal(t, "1: Task\n", buf.String(), "The trailing newline of the file is dropped")
+
+	_, err = parseItemTemplate("", filepath.Join(t.TempDir(), "missing.tmpl"), DateContext{})
+	require.Error(t, err)
+}
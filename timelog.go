@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TimeEntry is a work interval recorded for a task
+type TimeEntry struct {
+	Task    string     `json:"task"`              // Task description
+	Section []string   `json:"section,omitempty"` // Path of the sections the task is in
+	Start   time.Time  `json:"start"`
+	End     *time.Time `json:"end,omitempty"` // nil while the task is running
+}
+
+// Matches reports whether the entry was recorded for the item at the given section path
+func (e TimeEntry) Matches(item Item, path []string) bool {
+	return item.Type == TypeTask && e.Task == item.Content && slices.Equal(e.Section, path)
+}
+
+// findEntryTask returns the index of the task the entry was recorded for, or -1.
+// When several tasks match (e.g. occurrences of a recurring task) the first open one is preferred.
+func findEntryTask(items []Item, entry TimeEntry) int {
+	paths := sectionPaths(items)
+	found := -1
+	for i, item := range items {
+		if !entry.Matches(item, paths[i]) {
+			continue
+		}
+		if item.Checked != nil && !*item.Checked {
+			return i
+		}
+		if found < 0 {
+			found = i
+		}
+	}
+	return found
+}
+
+// Duration returns the time spent in the entry between since and now
+func (e TimeEntry) Duration(since, now time.Time) time.Duration {
+	start, end := e.Start, now
+	if e.End != nil {
+		end = *e.End
+	}
+	if start.Before(since) {
+		start = since
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// timeLogPath returns the path of the time log of the markdown file
+func timeLogPath(filePath string) string {
+	return sidecarPath(filePath, "timelog")
+}
+
+// loadTimeLog reads the time log of the markdown file, one JSON entry per line
+func loadTimeLog(filePath string) ([]TimeEntry, error) {
+	file, err := os.Open(timeLogPath(filePath))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to open time log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []TimeEntry
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry TimeEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, errorf(ExitParse, "invalid time log entry on line %d: %w", lineNumber, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading time log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// saveTimeLog writes the time log of the markdown file
+func saveTimeLog(filePath string, entries []TimeEntry) error {
+	var buf strings.Builder
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode time log entry: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteString("\n")
+	}
+
+	if err := os.WriteFile(timeLogPath(filePath), []byte(buf.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write time log: %w", err)
+	}
+	return nil
+}
+
+// runningEntry returns the index of the entry still running, or -1
+func runningEntry(entries []TimeEntry) int {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].End == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// stopRunning ends the running entry, if any, and returns its index or -1
+func stopRunning(entries []TimeEntry, now time.Time) int {
+	index := runningEntry(entries)
+	if index >= 0 {
+		entries[index].End = &now
+	}
+	return index
+}
+
+// formatDuration formats a duration rounded to the minute, e.g. 1h05m or 25m
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// formatRunning formats the marker shown next to the running task in listings
+func formatRunning(entry TimeEntry) string {
+	marker := "(running " + formatDuration(entry.Duration(time.Time{}, clock.Now())) + ")"
+	if shouldUseColor() {
+		return "\033[1;96m" + marker + "\033[0m" // Bold bright cyan
+	}
+	return marker
+}
+
+// parseSince parses the start of a report period.
+// Weekday names refer to the most recent such day (today included); other values are parsed as dates.
+func parseSince(value string, dates DateContext) (time.Time, error) {
+	today := startOfDay(dates.Today)
+
+	if day, err := parseWeekday(value); err == nil && value != "" {
+		days := (int(today.Weekday()) - int(day) + 7) % 7
+		return today.AddDate(0, 0, -days), nil
+	}
+
+	switch strings.ToLower(value) {
+	case "", "week", "this-week":
+		return dates.StartOfNextWeek().AddDate(0, 0, -7), nil
+	case "month", "this-month":
+		year, month, _ := today.Date()
+		return time.Date(year, month, 1, 0, 0, 0, 0, today.Location()), nil
+	}
+
+	since, ok := dates.Parse(value)
+	if !ok {
+		return time.Time{}, errorf(ExitParse, "invalid date '%s'", value)
+	}
+	return since, nil
+}
+
+// reportGroups lists how time can be grouped in reports
+var reportGroups = []string{"section", "task", "day"}
+
+// buildTimeReport sums the time spent per group since the given time
+func buildTimeReport(entries []TimeEntry, by string, since, now time.Time) (map[string]time.Duration, error) {
+	if !slices.Contains(reportGroups, by) {
+		return nil, errorf(ExitUsage, "invalid report grouping '%s' (must be one of %s)", by, strings.Join(reportGroups, ", "))
+	}
+
+	report := make(map[string]time.Duration)
+	for _, entry := range entries {
+		duration := entry.Duration(since, now)
+		if duration == 0 {
+			continue
+		}
+
+		var group string
+		switch by {
+		case "section":
+			group = formatSectionPath(entry.Section)
+		case "task":
+			group = formatSectionPath(append(slices.Clone(entry.Section), entry.Task))
+		case "day":
+			group = entry.Start.Format(time.DateOnly)
+		}
+		report[group] += duration
+	}
+
+	return report, nil
+}
+
+func newStartCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start <id>",
+		Short: "Start tracking time on a task",
+		Long: `Start tracking time on a task by specifying its ID, stopping any running task.
+Work intervals are recorded next to the markdown file (TODO.md uses TODO.timelog).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Parse the ID
+			index, err := parseItemID(args[0])
+			if err != nil {
+				return err
+			}
+			id := index + 1 // Keep original ID for display
+
+			tm, err := NewTaskManager(filePath)
+			if err != nil {
+				return err
+			}
+
+			item, err := tm.GetItem(index)
+			if err != nil {
+				return err
+			}
+			if item.Type != TypeTask {
+				return itemErrorf(ExitWrongType, id, "item at index %d is not a task", index)
+			}
+
+			entries, err := loadTimeLog(filePath)
+			if err != nil {
+				return err
+			}
+
+			now := clock.Now()
+			if stopped := stopRunning(entries, now); stopped >= 0 {
+				printStopped(entries[stopped], now)
+			}
+
+			entries = append(entries, TimeEntry{
+				Task:    item.Content,
+				Section: sectionPaths(tm.Items)[index],
+				Start:   now,
+			})
+
+			if err := saveTimeLog(filePath, entries); err != nil {
+				return err
+			}
+
+			if porcelain != "" {
+				printPorcelain("started", id)
+				return nil
+			}
+
+			fmt.Printf("Started task %d: %s\n", id, item.Content)
+			return nil
+		},
+	}
+
+	// Add completion for task IDs
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeTaskIDs(toComplete, false) // false = incomplete tasks only
+	}
+
+	return cmd
+}
+
+// printStopped reports the time tracked by an entry that was just stopped
+func printStopped(entry TimeEntry, now time.Time) {
+	duration := entry.Duration(time.Time{}, now)
+	if porcelain != "" {
+		printPorcelain("stopped", entry.Task, int(duration.Seconds()))
+		return
+	}
+	fmt.Printf("Stopped %s after %s\n", entry.Task, formatDuration(duration))
+}
+
+func newStopCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop tracking time on the running task",
+		Long:  "Stop tracking time on the task started with 'tasks start'.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := loadTimeLog(filePath)
+			if err != nil {
+				return err
+			}
+
+			now := clock.Now()
+			stopped := stopRunning(entries, now)
+			if stopped < 0 {
+				return errorf(ExitConflict, "no task is running")
+			}
+
+			if err := saveTimeLog(filePath, entries); err != nil {
+				return err
+			}
+
+			printStopped(entries[stopped], now)
+			return nil
+		},
+	}
+}
+
+func newReportCommand() *cobra.Command {
+	var (
+		since string
+		by    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarize tracked time",
+		Long: `Summarize the time tracked with 'tasks start' and 'tasks stop'.
+--since accepts a weekday (the most recent one), "week", "month" or a date (2025-08-10, yesterday, -7d),
+and defaults to the start of the current week. Time can be grouped by section, task or day.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadFileConfig(filePath)
+			if err != nil {
+				return err
+			}
+			dates := newDateContext(cfg)
+
+			sinceTime, err := parseSince(since, dates)
+			if err != nil {
+				return err
+			}
+
+			entries, err := loadTimeLog(filePath)
+			if err != nil {
+				return err
+			}
+
+			report, err := buildTimeReport(entries, by, sinceTime, dates.Today)
+			if err != nil {
+				return err
+			}
+
+			if porcelain != "" {
+				for _, group := range slices.Sorted(maps.Keys(report)) {
+					printPorcelain("time", group, int(report[group].Seconds()))
+				}
+				return nil
+			}
+
+			if len(report) == 0 {
+				fmt.Printf("No time tracked since %s\n", sinceTime.Format(time.DateOnly))
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintf(tw, "%s\tTIME\n", strings.ToUpper(by))
+
+			var total time.Duration
+			for _, group := range slices.Sorted(maps.Keys(report)) {
+				fmt.Fprintf(tw, "%s\t%s\n", group, formatDuration(report[group]))
+				total += report[group]
+			}
+			fmt.Fprintf(tw, "TOTAL\t%s\n", formatDuration(total))
+
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Start of the reported period (default: start of the week)")
+	cmd.Flags().StringVar(&by, "by", "section", "Group time by section, task or day")
+
+	return cmd
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeLog_RoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "TODO.md")
+
+	entries, err := loadTimeLog(filename)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	end := wednesday.Add(time.Hour)
+	entries = []TimeEntry{
+		{Task: "Write report", Section: []string{"Work"}, Start: wednesday, End: &end},
+		{Task: "Review", Start: end},
+	}
+	require.NoError(t, saveTimeLog(filename, entries))
+	require.FileExists(t, filepath.Join(filepath.Dir(filename), "TODO.timelog"))
+
+	loaded, err := loadTimeLog(filename)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	require.Equal(t, "Write report", loaded[0].Task)
+	require.True(t, loaded[0].End.Equal(end))
+	require.Nil(t, loaded[1].End)
+	require.Equal(t, 1, runningEntry(loaded))
+}
+
+func TestTimeLog_Invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "TODO.md")
+	require.NoError(t, os.WriteFile(timeLogPath(filename), []byte("{\"task\":\"ok\"}\nnot json\n"), 0o644))
+
+	_, err := loadTimeLog(filename)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 2")
+}
+
+func TestStopRunning(t *testing.T) {
+	end := wednesday.Add(time.Hour)
+	entries := []TimeEntry{{Task: "Done", Start: wednesday, End: &end}}
+	require.Equal(t, -1, stopRunning(entries, end))
+
+	entries = append(entries, TimeEntry{Task: "Running", Start: end})
+	now := end.Add(25 * time.Minute)
+	require.Equal(t, 1, stopRunning(entries, now))
+	require.Equal(t, 25*time.Minute, entries[1].Duration(time.Time{}, now))
+	require.Equal(t, -1, runningEntry(entries))
+}
+
+func TestTimeEntry_Duration(t *testing.T) {
+	end := wednesday.Add(2 * time.Hour)
+	entry := TimeEntry{Start: wednesday, End: &end}
+
+	require.Equal(t, 2*time.Hour, entry.Duration(time.Time{}, end.Add(time.Hour)))
+	require.Equal(t, 30*time.Minute, entry.Duration(end.Add(-30*time.Minute), end), "Clipped to the start of the period")
+	require.Zero(t, entry.Duration(end.Add(time.Hour), end.Add(2*time.Hour)), "Entirely before the period")
+
+	running := TimeEntry{Start: wednesday}
+	require.Equal(t, 10*time.Minute, running.Duration(time.Time{}, wednesday.Add(10*time.Minute)))
+}
+
+func TestFindEntryTask(t *testing.T) {
+	items := []Item{
+		{Type: TypeSection, Level: 1, Content: "Chores"},
+		{Type: TypeTask, Content: "Review", Checked: func() *bool { b := true; return &b }()},
+		{Type: TypeTask, Content: "Review", Checked: func() *bool { b := false; return &b }()},
+		{Type: TypeSection, Level: 1, Content: "Work"},
+		{Type: TypeTask, Content: "Report", Checked: func() *bool { b := true; return &b }()},
+	}
+
+	require.Equal(t, 2, findEntryTask(items, TimeEntry{Task: "Review", Section: []string{"Chores"}}), "Open tasks are preferred")
+	require.Equal(t, 4, findEntryTask(items, TimeEntry{Task: "Report", Section: []string{"Work"}}))
+	require.Equal(t, -1, findEntryTask(items, TimeEntry{Task: "Report", Section: []string{"Chores"}}))
+}
+
+func TestFormatDuration(t *testing.T) {
+	require.Equal(t, "0m", formatDuration(20*time.Second))
+	require.Equal(t, "25m", formatDuration(25*time.Minute))
+	require.Equal(t, "1h05m", formatDuration(65*time.Minute))
+	require.Equal(t, "12h00m", formatDuration(12*time.Hour))
+}
+
+func TestParseSince(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.Local) }
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"", day(11)},
+		{"monday", day(11)},
+		{"wednesday", day(13)},
+		{"thursday", day(7)},
+		{"month", day(1)},
+		{"yesterday", day(12)},
+		{"-7d", day(6)},
+		{"2025-08-01", day(1)},
+	}
+
+	for _, tc := range testCases {
+		since, err := parseSince(tc.input, dates)
+		require.NoError(t, err, "Input: %s", tc.input)
+		require.Equal(t, tc.expected, since, "Input: %s", tc.input)
+	}
+
+	_, err := parseSince("whenever", dates)
+	require.Error(t, err)
+}
+
+func TestBuildTimeReport(t *testing.T) {
+	at := func(d, h int) time.Time { return time.Date(2025, 8, d, h, 0, 0, 0, time.Local) }
+	end := func(d, h int) *time.Time { e := at(d, h); return &e }
+
+	entries := []TimeEntry{
+		{Task: "Old", Section: []string{"Work"}, Start: at(1, 9), End: end(1, 10)},
+		{Task: "Report", Section: []string{"Work"}, Start: at(11, 9), End: end(11, 11)},
+		{Task: "API", Section: []string{"Work", "Backend"}, Start: at(12, 9), End: end(12, 10)},
+		{Task: "Report", Section: []string{"Work"}, Start: at(13, 14)},
+	}
+	since, now := at(11, 0), at(13, 15)
+
+	report, err := buildTimeReport(entries, "section", since, now)
+	require.NoError(t, err)
+	require.Equal(t, map[string]time.Duration{"Work": 3 * time.Hour, "Work / Backend": time.Hour}, report)
+
+	report, err = buildTimeReport(entries, "task", since, now)
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Hour, report["Work / Report"])
+
+	report, err = buildTimeReport(entries, "day", since, now)
+	require.NoError(t, err)
+	require.Equal(t, map[string]time.Duration{"2025-08-11": 2 * time.Hour, "2025-08-12": time.Hour, "2025-08-13": time.Hour}, report)
+
+	_, err = buildTimeReport(entries, "owner", since, now)
+	require.Error(t, err)
+}
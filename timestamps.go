@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultStaleDays is the age in days after which an open task is considered stale
+const defaultStaleDays = 30
+
+// today returns the current date in ISO format, as stored in created and completed metadata
+func (dc DateContext) today() string {
+	return dc.Today.Format(time.DateOnly)
+}
+
+// stampCreated sets the created date of new task metadata, unless it was given explicitly
+func stampCreated(metadata map[string]string, dates DateContext) {
+	if _, ok := metadata["created"]; !ok {
+		metadata["created"] = dates.today()
+	}
+}
+
+// TaskAge returns the number of days since the task was created, if it has a created date
+func (dc DateContext) TaskAge(item Item) (int, bool) {
+	created, ok := dc.Parse(item.Metadata["created"])
+	if !ok {
+		return 0, false
+	}
+	return int(startOfDay(dc.Today).Sub(created).Hours() / 24), true
+}
+
+// IsStale reports whether the item is an open task created more than days ago
+func (dc DateContext) IsStale(item Item, days int) bool {
+	if item.Type != TypeTask || (item.Checked != nil && *item.Checked) {
+		return false
+	}
+	age, ok := dc.TaskAge(item)
+	return ok && age > days
+}
+
+// Within reports whether the date in the metadata key falls in the last days (today included)
+func (dc DateContext) Within(item Item, key string, days int) bool {
+	date, ok := dc.Parse(item.Metadata[key])
+	return ok && !date.Before(startOfDay(dc.Today).AddDate(0, 0, -days+1)) && !date.After(dc.Today)
+}
+
+// staleTasks returns the indices of the stale tasks, oldest first
+func staleTasks(items []Item, dates DateContext, days int) []int {
+	var stale []int
+	for i, item := range items {
+		if dates.IsStale(item, days) {
+			stale = append(stale, i)
+		}
+	}
+
+	slices.SortStableFunc(stale, func(a, b int) int {
+		ageA, _ := dates.TaskAge(items[a])
+		ageB, _ := dates.TaskAge(items[b])
+		return ageB - ageA
+	})
+	return stale
+}
+
+func newStaleCommand() *cobra.Command {
+	var days int
+
+	cmd := &cobra.Command{
+		Use:   "stale",
+		Short: "List open tasks created long ago",
+		Long: `List open tasks whose created date is older than --days, oldest first.
+Tasks get a created date when added if timestamps are enabled ('tasks config timestamps true').`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadFileConfig(filePath)
+			if err != nil {
+				return err
+			}
+			items, err := parseMarkdownFile(filePath)
+			if err != nil {
+				return err
+			}
+			dates := newDateContext(cfg)
+			hidden := hiddenItems(items, dates)
+			paths := sectionPaths(items)
+
+			found := false
+			for _, index := range staleTasks(items, dates, days) {
+				if hidden[index] {
+					continue
+				}
+				age, _ := dates.TaskAge(items[index])
+				found = true
+				if porcelain != "" {
+					printPorcelain("stale", append([]any{age}, itemFields(items[index], index, paths[index])...)...)
+					continue
+				}
+				fmt.Printf("%s (%d days old)\n", formatItem(items[index], index), age)
+			}
+
+			if !found && porcelain == "" {
+				fmt.Printf("No open tasks older than %d days\n", days)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&days, "days", defaultStaleDays, "Age in days after which an open task is stale")
+
+	return cmd
+}
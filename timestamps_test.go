@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStampCreated(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+
+	metadata := map[string]string{"due": "2025-08-15"}
+	stampCreated(metadata, dates)
+	require.Equal(t, map[string]string{"due": "2025-08-15", "created": "2025-08-13"}, metadata)
+
+	metadata = map[string]string{"created": "2025-01-01"}
+	stampCreated(metadata, dates)
+	require.Equal(t, "2025-01-01", metadata["created"], "Explicit created dates are kept")
+}
+
+func TestDateContext_TaskAge(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+
+	age, ok := dates.TaskAge(Item{Type: TypeTask, Metadata: map[string]string{"created": "2025-08-01"}})
+	require.True(t, ok)
+	require.Equal(t, 12, age)
+
+	age, ok = dates.TaskAge(Item{Type: TypeTask, Metadata: map[string]string{"created": "2025-08-13"}})
+	require.True(t, ok)
+	require.Equal(t, 0, age)
+
+	_, ok = dates.TaskAge(Item{Type: TypeTask})
+	require.False(t, ok)
+}
+
+func TestDateContext_Within(t *testing.T) {
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	task := func(completed string) Item {
+		return Item{Type: TypeTask, Metadata: map[string]string{"completed": completed}}
+	}
+
+	require.True(t, dates.Within(task("2025-08-13"), "completed", 7))
+	require.True(t, dates.Within(task("2025-08-07"), "completed", 7))
+	require.False(t, dates.Within(task("2025-08-06"), "completed", 7))
+	require.False(t, dates.Within(task("2025-08-14"), "completed", 7), "Future dates are not recent")
+	require.False(t, dates.Within(Item{Type: TypeTask}, "completed", 7))
+}
+
+func TestStaleTasks(t *testing.T) {
+	content := `# Project
+- [ ] Old created:2025-06-01
+- [ ] Older created:2025-05-01
+- [x] Done created:2025-01-01
+- [ ] Recent created:2025-08-10
+- [ ] No date
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	dates := DateContext{Today: wednesday, WeekStart: time.Monday}
+	require.Equal(t, []int{2, 1}, staleTasks(items, dates, 30))
+	require.Equal(t, []int{2, 1, 4}, staleTasks(items, dates, 1))
+	require.Empty(t, staleTasks(items, dates, 365))
+}
+
+func TestComputeStats_Activity(t *testing.T) {
+	content := `- [ ] Stale created:2025-06-01
+- [ ] New created:2025-08-12
+- [x] Finished created:2025-08-01 completed:2025-08-11
+- [x] Finished long ago completed:2025-07-01
+`
+	filename := createTestFile(t, content)
+	items, err := parseMarkdownFile(filename)
+	require.NoError(t, err)
+
+	stats := computeStats("TODO.md", items, DateContext{Today: wednesday, WeekStart: time.Monday})
+	require.Equal(t, ActivityStats{Created: 1, Completed: 1, Stale: 1}, stats.Activity)
+}
+
+func TestConfigKeys_Timestamps(t *testing.T) {
+	var cfg FileConfig
+
+	require.Equal(t, "false", configKeys["timestamps"].Get(&cfg))
+
+	require.NoError(t, configKeys["timestamps"].Set(&cfg, "true"))
+	require.True(t, cfg.Timestamps)
+	require.Equal(t, "true", configKeys["timestamps"].Get(&cfg))
+
+	require.Error(t, configKeys["timestamps"].Set(&cfg, "sometimes"))
+
+	require.NoError(t, configKeys["timestamps"].Set(&cfg, ""))
+	require.False(t, cfg.Timestamps)
+}
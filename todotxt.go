@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// todo.txt stores one task per line:
+//
+//	x 2025-08-13 2025-08-01 (A) Call mom +Family @phone due:2025-08-15
+//
+// A completed task starts with "x" and its completion date, then comes the priority of open tasks,
+// the creation date, and the description with +project, @context and key:value tokens.
+// Completed tasks keep their priority as pri:A.
+
+// isTodoTxtDate reports whether s is a todo.txt date
+func isTodoTxtDate(s string) bool {
+	_, err := time.Parse(time.DateOnly, s)
+	return err == nil
+}
+
+// isTodoTxtPriority reports whether s is a todo.txt priority such as (A)
+func isTodoTxtPriority(s string) bool {
+	return len(s) == 3 && s[0] == '(' && s[1] >= 'A' && s[1] <= 'Z' && s[2] == ')'
+}
+
+// parseTodoTxtLine parses a todo.txt line into a task and the project it belongs to, if any.
+// The first +project gives the section, other projects are kept in the description.
+func parseTodoTxtLine(line string) (Item, string) {
+	words := strings.Fields(line)
+	metadata := make(map[string]string)
+	checked := false
+
+	if len(words) > 0 && words[0] == "x" {
+		checked = true
+		words = words[1:]
+		if len(words) > 0 && isTodoTxtDate(words[0]) {
+			metadata["completed"] = words[0]
+			words = words[1:]
+		}
+	} else if len(words) > 0 && isTodoTxtPriority(words[0]) {
+		metadata["priority"] = words[0][1:2]
+		words = words[1:]
+	}
+
+	if len(words) > 0 && isTodoTxtDate(words[0]) {
+		metadata["created"] = words[0]
+		words = words[1:]
+	}
+
+	var project string
+	var description []string
+	for _, word := range words {
+		if name, ok := strings.CutPrefix(word, "+"); ok && name != "" && project == "" {
+			project = name
+			continue
+		}
+
+		key, value, ok := strings.Cut(word, ":")
+		if ok && isIdentifier(key) && value != "" && !strings.HasPrefix(value, "//") {
+			if key == "pri" {
+				key = "priority"
+			}
+			metadata[key] = value
+			continue
+		}
+
+		description = append(description, word)
+	}
+
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+
+	return Item{
+		Type:     TypeTask,
+		Content:  strings.Join(description, " "),
+		Checked:  &checked,
+		Metadata: metadata,
+		Tags:     parseTags(strings.Join(description, " ")),
+	}, project
+}
+
+// importTodoTxt reads todo.txt tasks. Tasks without a project come first,
+// followed by a section per project in order of appearance.
+func importTodoTxt(r io.Reader) ([]Item, error) {
+	var tasks []Item
+	var paths [][]string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		task, project := parseTodoTxtLine(line)
+		if task.Content == "" {
+			continue
+		}
+
+		var path []string
+		if project != "" {
+			path = []string{project}
+		}
+		tasks = append(tasks, task)
+		paths = append(paths, path)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading todo.txt: %w", err)
+	}
+
+	return buildSections(tasks, paths), nil
+}
+
+// todoTxtPriority converts a priority to a todo.txt letter, if possible
+func todoTxtPriority(value string) (string, bool) {
+	rank, ok := priorityRank(value)
+	if !ok || rank < 0 || rank > 25 {
+		return "", false
+	}
+	if len(value) == 1 {
+		return strings.ToUpper(value), true
+	}
+	return string(rune('A' + rank)), true
+}
+
+// formatTodoTxtLine formats a task as a todo.txt line, the innermost section becoming its project
+func formatTodoTxtLine(item Item, path []string) string {
+	metadata := maps.Clone(item.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	done := item.Checked != nil && *item.Checked
+
+	var parts []string
+	priority, hasPriority := todoTxtPriority(metadata["priority"])
+	if hasPriority {
+		delete(metadata, "priority")
+	}
+
+	created, hasCreated := metadata["created"]
+	hasCreated = hasCreated && isTodoTxtDate(created)
+
+	if done {
+		parts = append(parts, "x")
+		// The completion date can only be given along with the creation date
+		if completed, ok := metadata["completed"]; ok && hasCreated && isTodoTxtDate(completed) {
+			parts = append(parts, completed)
+			delete(metadata, "completed")
+		}
+		if hasPriority {
+			metadata["pri"] = priority
+		}
+	} else if hasPriority {
+		parts = append(parts, "("+priority+")")
+	}
+
+	if hasCreated {
+		parts = append(parts, created)
+		delete(metadata, "created")
+	}
+
+	parts = append(parts, item.Content)
+
+	if len(path) > 0 {
+		parts = append(parts, "+"+strings.Join(strings.Fields(path[len(path)-1]), "-"))
+	}
+
+	// todo.txt values can't contain spaces
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		parts = append(parts, key+":"+strings.Join(strings.Fields(metadata[key]), "_"))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// exportTodoTxt writes every task as a todo.txt line
+func exportTodoTxt(w io.Writer, items []Item) error {
+	paths := sectionPaths(items)
+	for i, item := range items {
+		if item.Type != TypeTask {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, formatTodoTxtLine(item, paths[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
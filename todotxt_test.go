@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTodoTxtLine(t *testing.T) {
+	task, project := parseTodoTxtLine("(A) 2025-08-01 Call mom +Family @phone due:2025-08-15")
+	require.Equal(t, "Family", project)
+	require.Equal(t, "Call mom @phone", task.Content)
+	require.False(t, *task.Checked)
+	require.Equal(t, map[string]string{"priority": "A", "created": "2025-08-01", "due": "2025-08-15"}, task.Metadata)
+	require.Equal(t, []string{"@phone"}, task.Tags)
+
+	task, project = parseTodoTxtLine("x 2025-08-13 2025-08-01 Pay rent +Home +Bills pri:B")
+	require.Equal(t, "Home", project)
+	require.Equal(t, "Pay rent +Bills", task.Content, "Only the first project becomes the section")
+	require.True(t, *task.Checked)
+	require.Equal(t, map[string]string{"priority": "B", "created": "2025-08-01", "completed": "2025-08-13"}, task.Metadata)
+
+	task, project = parseTodoTxtLine("Read http://example.com (A) later")
+	require.Empty(t, project)
+	require.Equal(t, "Read http://example.com (A) later", task.Content)
+	require.Nil(t, task.Metadata)
+}
+
+func TestImportTodoTxt(t *testing.T) {
+	input := `Buy milk
+(B) Fix bug +Work
+
+Water plants +Home
+x Write report +Work
+`
+	items, err := importTodoTxt(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Buy milk", "Work", "Fix bug", "Write report", "Home", "Water plants"}, contents(items))
+	require.Equal(t, TypeSection, items[1].Type)
+	require.Equal(t, 1, items[1].Level)
+	require.True(t, *items[3].Checked)
+}
+
+func TestFormatTodoTxtLine(t *testing.T) {
+	open := false
+	done := true
+
+	testCases := []struct {
+		item     Item
+		path     []string
+		expected string
+	}{
+		{
+			Item{Type: TypeTask, Content: "Call mom", Checked: &open, Metadata: map[string]string{"priority": "high", "created": "2025-08-01", "due": "2025-08-15"}},
+			[]string{"Personal", "Family Stuff"},
+			"(B) 2025-08-01 Call mom +Family-Stuff due:2025-08-15",
+		},
+		{
+			Item{Type: TypeTask, Content: "Pay rent", Checked: &done, Metadata: map[string]string{"priority": "a", "created": "2025-08-01", "completed": "2025-08-13"}},
+			nil,
+			"x 2025-08-13 2025-08-01 Pay rent pri:A",
+		},
+		{
+			Item{Type: TypeTask, Content: "No creation date", Checked: &done, Metadata: map[string]string{"completed": "2025-08-13", "note": "two words"}},
+			nil,
+			"x No creation date completed:2025-08-13 note:two_words",
+		},
+		{
+			Item{Type: TypeTask, Content: "Odd priority", Checked: &open, Metadata: map[string]string{"priority": "someday"}},
+			nil,
+			"Odd priority priority:someday",
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, formatTodoTxtLine(tc.item, tc.path))
+	}
+}
+
+func TestTodoTxt_RoundTrip(t *testing.T) {
+	input := `Buy milk @store
+(A) 2025-08-01 Call mom +Family due:2025-08-15
+x 2025-08-13 2025-08-01 Pay rent +Family pri:C
+`
+	items, err := importTodoTxt(strings.NewReader(input))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, exportTodoTxt(&buf, items))
+	require.Equal(t, input, buf.String())
+}